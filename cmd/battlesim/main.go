@@ -0,0 +1,279 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/database"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/game/pokebat"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// Config - Tham số của một lần chạy mô phỏng
+type Config struct {
+	Battles    int      `json:"battles"`
+	Seed       int64    `json:"seed"`
+	Workers    int      `json:"workers"`
+	TeamSize   int      `json:"team_size"`
+	Team1      []string `json:"team1,omitempty"`
+	Team2      []string `json:"team2,omitempty"`
+	Level      int      `json:"level"` // 0 = ngẫu nhiên
+	EV         float64  `json:"ev"`    // 0 = ngẫu nhiên trong [MinEV, MaxEV]
+	Agent1     string   `json:"agent1"`
+	Agent2     string   `json:"agent2"`
+	MaxActions int      `json:"max_actions"`
+}
+
+// pokemonResult - Kết quả của một Pokemon trong một battle
+type pokemonResult struct {
+	Species string
+	Types   []string
+	EV      float64
+	Won     bool
+}
+
+// battleResult - Kết quả một battle
+type battleResult struct {
+	Index   int
+	Draw    bool
+	Err     error
+	Pokemon []pokemonResult
+	Damage  map[string]moveDamage
+}
+
+type moveDamage struct {
+	Uses   int
+	Damage int
+}
+
+func main() {
+	cfg := Config{}
+	var team1, team2, pokedexPath, csvPath, jsonPath string
+
+	flag.IntVar(&cfg.Battles, "battles", 1000, "number of battles to simulate")
+	flag.Int64Var(&cfg.Seed, "seed", 1, "base random seed")
+	flag.IntVar(&cfg.Workers, "workers", runtime.NumCPU(), "number of parallel workers")
+	flag.IntVar(&cfg.TeamSize, "team-size", constants.MaxBattlePokemon, "pokemon per random team")
+	flag.StringVar(&team1, "team1", "", "comma separated species for side 1 (random if empty)")
+	flag.StringVar(&team2, "team2", "", "comma separated species for side 2 (random if empty)")
+	flag.IntVar(&cfg.Level, "level", 50, "pokemon level (0 = random)")
+	flag.Float64Var(&cfg.EV, "ev", 0, "pokemon EV (0 = random between min and max EV)")
+	flag.StringVar(&cfg.Agent1, "agent1", "greedy", "agent for side 1: random, greedy, lookahead")
+	flag.StringVar(&cfg.Agent2, "agent2", "greedy", "agent for side 2: random, greedy, lookahead")
+	flag.IntVar(&cfg.MaxActions, "max-actions", 1000, "actions before a battle is declared a draw")
+	flag.StringVar(&pokedexPath, "pokedex", constants.PokedexPath, "path to pokedex.json")
+	flag.StringVar(&csvPath, "csv", "", "write aggregated results as CSV")
+	flag.StringVar(&jsonPath, "json", "", "write aggregated results as JSON")
+	flag.Parse()
+
+	cfg.Team1 = splitTeam(team1)
+	cfg.Team2 = splitTeam(team2)
+	if err := validateConfig(cfg); err != nil {
+		log.Fatal(err)
+	}
+
+	pokedex, err := database.LoadPokedex(pokedexPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, name := range append(append([]string{}, cfg.Team1...), cfg.Team2...) {
+		if _, err := pokedex.Find(name); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	report := newReport(cfg)
+	for result := range runBattles(cfg, pokedex) {
+		if result.Err != nil {
+			log.Printf("battle %d failed: %v", result.Index, result.Err)
+		}
+		report.add(result)
+	}
+
+	report.print(os.Stdout)
+	if csvPath != "" {
+		if err := report.writeCSV(csvPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if jsonPath != "" {
+		if err := report.writeJSON(jsonPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func splitTeam(value string) []string {
+	team := make([]string, 0)
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			team = append(team, name)
+		}
+	}
+	return team
+}
+
+func validateConfig(cfg Config) error {
+	if cfg.Battles < 1 {
+		return fmt.Errorf("battles must be positive")
+	}
+	if cfg.Workers < 1 {
+		return fmt.Errorf("workers must be positive")
+	}
+	if cfg.TeamSize < 1 {
+		return fmt.Errorf("team size must be positive")
+	}
+	if cfg.Level < 0 || cfg.Level > constants.MaxLevel {
+		return fmt.Errorf("level must be between 0 and %d", constants.MaxLevel)
+	}
+	if cfg.EV != 0 && (cfg.EV < constants.MinEV || cfg.EV > constants.MaxEV) {
+		return fmt.Errorf("ev must be between %.1f and %.1f", constants.MinEV, constants.MaxEV)
+	}
+	for _, name := range []string{cfg.Agent1, cfg.Agent2} {
+		if _, err := pokebat.NewAgent(name, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runBattles - Chia battle cho các worker; kết quả chỉ phụ thuộc seed và số thứ tự battle
+func runBattles(cfg Config, pokedex *database.Pokedex) <-chan battleResult {
+	jobs := make(chan int)
+	results := make(chan battleResult, cfg.Workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				results <- simulateBattle(cfg, pokedex, index)
+			}
+		}()
+	}
+
+	go func() {
+		for i := 0; i < cfg.Battles; i++ {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+func simulateBattle(cfg Config, pokedex *database.Pokedex, index int) battleResult {
+	seed := cfg.Seed + int64(index)
+	rng := rand.New(rand.NewSource(seed))
+	result := battleResult{Index: index, Damage: make(map[string]moveDamage)}
+
+	team1, err := buildTeam(cfg, pokedex, cfg.Team1, rng)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	team2, err := buildTeam(cfg, pokedex, cfg.Team2, rng)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	// Ghi lại team trước khi battle làm thay đổi HP
+	initial := map[string][]models.Pokemon{"side1": copyTeam(team1), "side2": copyTeam(team2)}
+
+	battle, err := pokebat.NewBattleWithTeams(fmt.Sprintf("sim-%d", index), "side1", team1, "side2", team2, seed)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	agent1, _ := pokebat.NewAgent(cfg.Agent1, seed*2)
+	agent2, _ := pokebat.NewAgent(cfg.Agent2, seed*2+1)
+	if err := pokebat.PlayAgents(battle, agent1, agent2, cfg.MaxActions); err != nil {
+		result.Draw = true
+	}
+
+	winner := battle.GetWinner()
+	result.Draw = result.Draw || winner == ""
+	for side, team := range initial {
+		for _, pokemon := range team {
+			result.Pokemon = append(result.Pokemon, pokemonResult{
+				Species: speciesName(pokemon),
+				Types:   pokemon.Types,
+				EV:      pokemon.EV,
+				Won:     !result.Draw && side == winner,
+			})
+		}
+	}
+
+	for _, event := range battle.GetEvents() {
+		if event.Type != pokebat.EventDamage || event.Damage == nil {
+			continue
+		}
+		stats := result.Damage[event.Move]
+		stats.Uses++
+		stats.Damage += event.Damage.Damage
+		result.Damage[event.Move] = stats
+	}
+	return result
+}
+
+// buildTeam - Tạo team từ danh sách loài chỉ định hoặc chọn ngẫu nhiên từ Pokedex
+func buildTeam(cfg Config, pokedex *database.Pokedex, names []string, rng *rand.Rand) ([]*models.Pokemon, error) {
+	entries := make([]*database.PokedexEntry, 0, cfg.TeamSize)
+	if len(names) > 0 {
+		for _, name := range names {
+			entry, err := pokedex.Find(name)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		}
+	} else {
+		for i := 0; i < cfg.TeamSize; i++ {
+			entries = append(entries, pokedex.Random(rng))
+		}
+	}
+
+	team := make([]*models.Pokemon, 0, len(entries))
+	for _, entry := range entries {
+		level := cfg.Level
+		if level == 0 {
+			level = rng.Intn(constants.MaxLevel) + 1
+		}
+		ev := cfg.EV
+		if ev == 0 {
+			ev = constants.MinEV + rng.Float64()*(constants.MaxEV-constants.MinEV)
+		}
+
+		pokemon, err := models.NewPokemon(entry.ToMap(), level, ev)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s: %v", entry.FullName, err)
+		}
+		team = append(team, pokemon)
+	}
+	return team, nil
+}
+
+func copyTeam(team []*models.Pokemon) []models.Pokemon {
+	copies := make([]models.Pokemon, len(team))
+	for i, pokemon := range team {
+		copies[i] = *pokemon
+	}
+	return copies
+}
+
+// speciesName - Tên loài phân biệt các dạng (Mega, Origin Forme...)
+func speciesName(pokemon models.Pokemon) string {
+	entry := database.PokedexEntry{FullName: pokemon.FullName, Name: pokemon.Name}
+	return entry.DisplayName()
+}
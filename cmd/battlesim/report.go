@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+)
+
+const (
+	evBucketSize  = 0.1
+	printTopCount = 10
+	printMinGames = 20
+)
+
+// Stat - Số trận và số trận thắng của một nhóm (loài, type, EV...)
+type Stat struct {
+	Key     string  `json:"key"`
+	Games   int     `json:"games"`
+	Wins    int     `json:"wins"`
+	WinRate float64 `json:"win_rate"`
+}
+
+// MoveStat - Thống kê sát thương theo loại đòn
+type MoveStat struct {
+	Move          string  `json:"move"`
+	Uses          int     `json:"uses"`
+	TotalDamage   int     `json:"total_damage"`
+	AverageDamage float64 `json:"average_damage"`
+}
+
+// Report - Kết quả tổng hợp của toàn bộ mô phỏng
+type Report struct {
+	Config    Config     `json:"config"`
+	Battles   int        `json:"battles"`
+	Draws     int        `json:"draws"`
+	Errors    int        `json:"errors"`
+	BySpecies []Stat     `json:"by_species"`
+	ByType    []Stat     `json:"by_type"`
+	ByEV      []Stat     `json:"by_ev"`
+	ByMove    []MoveStat `json:"by_move"`
+
+	species map[string]*Stat
+	types   map[string]*Stat
+	evs     map[string]*Stat
+	moves   map[string]*MoveStat
+}
+
+func newReport(cfg Config) *Report {
+	return &Report{
+		Config:  cfg,
+		species: make(map[string]*Stat),
+		types:   make(map[string]*Stat),
+		evs:     make(map[string]*Stat),
+		moves:   make(map[string]*MoveStat),
+	}
+}
+
+func (r *Report) add(result battleResult) {
+	r.Battles++
+	if result.Err != nil {
+		r.Errors++
+		return
+	}
+	if result.Draw {
+		r.Draws++
+	}
+
+	for _, pokemon := range result.Pokemon {
+		count(r.species, pokemon.Species, pokemon.Won)
+		for _, pokemonType := range pokemon.Types {
+			count(r.types, pokemonType, pokemon.Won)
+		}
+		count(r.evs, evBucket(pokemon.EV), pokemon.Won)
+	}
+
+	for move, damage := range result.Damage {
+		stat, exists := r.moves[move]
+		if !exists {
+			stat = &MoveStat{Move: move}
+			r.moves[move] = stat
+		}
+		stat.Uses += damage.Uses
+		stat.TotalDamage += damage.Damage
+	}
+}
+
+func count(stats map[string]*Stat, key string, won bool) {
+	stat, exists := stats[key]
+	if !exists {
+		stat = &Stat{Key: key}
+		stats[key] = stat
+	}
+	stat.Games++
+	if won {
+		stat.Wins++
+	}
+}
+
+// evBucket - Nhóm EV theo khoảng 0.1, EV tối đa thuộc nhóm cuối. Cộng thêm một sai số nhỏ
+// để EV nằm đúng biên (0.6, 0.7...) không bị sai số dấu chấm động đẩy xuống nhóm dưới.
+func evBucket(ev float64) string {
+	buckets := int(math.Round((constants.MaxEV - constants.MinEV) / evBucketSize))
+	index := int(math.Floor((ev-constants.MinEV)/evBucketSize + 1e-9))
+	if index >= buckets {
+		index = buckets - 1
+	}
+	if index < 0 {
+		index = 0
+	}
+	low := constants.MinEV + float64(index)*evBucketSize
+	return fmt.Sprintf("%.1f-%.1f", low, low+evBucketSize)
+}
+
+// finalize - Tính tỉ lệ thắng và sắp xếp kết quả
+func (r *Report) finalize() {
+	r.BySpecies = sortedStats(r.species, false)
+	r.ByType = sortedStats(r.types, false)
+	r.ByEV = sortedStats(r.evs, true)
+
+	r.ByMove = make([]MoveStat, 0, len(r.moves))
+	for _, stat := range r.moves {
+		if stat.Uses > 0 {
+			stat.AverageDamage = float64(stat.TotalDamage) / float64(stat.Uses)
+		}
+		r.ByMove = append(r.ByMove, *stat)
+	}
+	sort.Slice(r.ByMove, func(i, j int) bool { return r.ByMove[i].Move < r.ByMove[j].Move })
+}
+
+func sortedStats(stats map[string]*Stat, byKey bool) []Stat {
+	result := make([]Stat, 0, len(stats))
+	for _, stat := range stats {
+		stat.WinRate = float64(stat.Wins) / float64(stat.Games)
+		result = append(result, *stat)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !byKey && result[i].WinRate != result[j].WinRate {
+			return result[i].WinRate > result[j].WinRate
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+func (r *Report) print(w io.Writer) {
+	r.finalize()
+
+	fmt.Fprintf(w, "Simulated %d battles (%d draws, %d errors), agents %s vs %s, seed %d\n",
+		r.Battles, r.Draws, r.Errors, r.Config.Agent1, r.Config.Agent2, r.Config.Seed)
+
+	fmt.Fprintln(w, "\nWin rate by EV:")
+	printStats(w, r.ByEV, len(r.ByEV), 0)
+
+	fmt.Fprintln(w, "\nWin rate by type:")
+	printStats(w, r.ByType, len(r.ByType), 0)
+
+	fmt.Fprintf(w, "\nTop %d species (min %d games):\n", printTopCount, printMinGames)
+	printStats(w, r.BySpecies, printTopCount, printMinGames)
+
+	fmt.Fprintln(w, "\nDamage by move:")
+	for _, stat := range r.ByMove {
+		fmt.Fprintf(w, "  %-10s uses %8d  total %10d  avg %8.1f\n",
+			stat.Move, stat.Uses, stat.TotalDamage, stat.AverageDamage)
+	}
+}
+
+func printStats(w io.Writer, stats []Stat, limit, minGames int) {
+	printed := 0
+	for _, stat := range stats {
+		if printed >= limit {
+			return
+		}
+		if stat.Games < minGames {
+			continue
+		}
+		fmt.Fprintf(w, "  %-24s games %7d  wins %7d  win rate %6.2f%%\n",
+			stat.Key, stat.Games, stat.Wins, stat.WinRate*100)
+		printed++
+	}
+}
+
+// writeCSV - Ghi kết quả dạng CSV: category,key,games,wins,win_rate
+func (r *Report) writeCSV(path string) error {
+	r.finalize()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create csv: %v", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"category", "key", "games", "wins", "win_rate"})
+	categories := []struct {
+		name  string
+		stats []Stat
+	}{
+		{"species", r.BySpecies},
+		{"type", r.ByType},
+		{"ev", r.ByEV},
+	}
+	for _, category := range categories {
+		for _, stat := range category.stats {
+			writer.Write([]string{
+				category.name,
+				stat.Key,
+				strconv.Itoa(stat.Games),
+				strconv.Itoa(stat.Wins),
+				strconv.FormatFloat(stat.WinRate, 'f', 4, 64),
+			})
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %v", err)
+	}
+	return nil
+}
+
+// writeJSON - Ghi toàn bộ report dạng JSON
+func (r *Report) writeJSON(path string) error {
+	r.finalize()
+
+	data, err := json.MarshalIndent(r, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write json: %v", err)
+	}
+	return nil
+}
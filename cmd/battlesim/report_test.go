@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestEVBucket(t *testing.T) {
+	tests := []struct {
+		ev   float64
+		want string
+	}{
+		{0.5, "0.5-0.6"},
+		{0.55, "0.5-0.6"},
+		{0.599, "0.5-0.6"},
+		{0.6, "0.6-0.7"},
+		{0.7, "0.7-0.8"},
+		{0.75, "0.7-0.8"},
+		{0.8, "0.8-0.9"},
+		{0.9, "0.9-1.0"},
+		{0.99, "0.9-1.0"},
+		{1.0, "0.9-1.0"},
+		{0.1 * 7, "0.7-0.8"},
+		{0.4, "0.5-0.6"},
+		{1.2, "0.9-1.0"},
+	}
+	for _, test := range tests {
+		if got := evBucket(test.ev); got != test.want {
+			t.Errorf("evBucket(%v) = %s, want %s", test.ev, got, test.want)
+		}
+	}
+}
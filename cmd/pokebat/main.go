@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/game/pokebat"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: pokebat replay <file>")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "replay":
+		if len(os.Args) != 3 {
+			usage()
+		}
+		if err := runReplay(os.Args[2]); err != nil {
+			fmt.Fprintf(os.Stderr, "replay failed: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		usage()
+	}
+}
+
+// runReplay - Mô phỏng lại battle từ file replay và kiểm tra kết quả
+func runReplay(filename string) error {
+	replay, err := pokebat.LoadReplay(filename)
+	if err != nil {
+		return err
+	}
+
+	battle, err := replay.Verify()
+	if battle != nil {
+		for _, event := range battle.GetEvents() {
+			fmt.Println(event.String())
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Replay %s verified: %d actions, %d events, winner %q\n",
+		replay.BattleID, len(replay.Actions), len(replay.Events), replay.WinnerID)
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/config"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/game/pokebat"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/game/pokecat"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/game/tournament"
+	"github.com/TaViKhang/pokecat-n-pokebat/pkg/network"
+)
+
+func main() {
+	cfg, err := config.LoadDefault()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	manager := pokebat.NewManager()
+	roster, err := pokebat.LoadNPCRoster(constants.NPCRosterPath, time.Now().UnixNano())
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, npc := range roster {
+		if err := manager.RegisterNPC(npc); err != nil {
+			log.Fatal(err)
+		}
+	}
+	server := network.NewServer(manager, cfg)
+
+	tournaments, err := tournament.NewManager(manager, server.OnlinePlayer)
+	if err != nil {
+		log.Fatal(err)
+	}
+	server.SetTournaments(tournaments)
+
+	terrain, err := pokecat.NewTerrain(cfg.World)
+	if err != nil {
+		log.Fatal(err)
+	}
+	world := pokecat.NewGrid(cfg)
+	if err := world.SetTerrain(terrain); err != nil {
+		log.Fatal(err)
+	}
+	manager.OnChallenge = world.StopAutoModeOnChallenge
+	if cfg.World.WildEncounters {
+		if err := world.EnableEncounters(manager, cfg.World.EncounterFormat); err != nil {
+			log.Fatal(err)
+		}
+	}
+	server.SetWorld(world)
+
+	// Dọn dẹp khi nhận tín hiệu dừng
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		log.Println("Shutting down server...")
+		tournaments.Cleanup()
+		server.Shutdown()
+		world.Cleanup()
+		manager.Cleanup()
+	}()
+
+	if err := server.ListenAndServe(fmt.Sprintf(":%d", cfg.Network.TCPPort)); err != nil {
+		log.Fatal(err)
+	}
+}
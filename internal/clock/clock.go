@@ -0,0 +1,52 @@
+package clock
+
+import "time"
+
+// Clock - Nguồn thời gian có thể thay thế, để các routine theo thời gian chạy được với đồng hồ giả
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Ticker - Ticker lấy từ Clock
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Timer - Timer lấy từ Clock.AfterFunc
+type Timer interface {
+	Stop() bool
+}
+
+type realClock struct{}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+// Real - Clock dùng thời gian hệ thống
+func Real() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{ticker: time.NewTicker(d)}
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t realTicker) Stop() {
+	t.ticker.Stop()
+}
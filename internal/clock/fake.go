@@ -0,0 +1,154 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake - Clock chỉ tiến khi gọi Advance, dùng để kiểm thử spawn, cooldown, auto-save và timeout
+// mà không phải chờ thời gian thật
+type Fake struct {
+	now     time.Time
+	nextID  uint64
+	waiters map[uint64]*fakeWaiter
+	mu      sync.Mutex
+}
+
+// fakeWaiter - Ticker (period > 0) hoặc timer AfterFunc đang chờ tới hạn
+type fakeWaiter struct {
+	id     uint64
+	at     time.Time
+	period time.Duration
+	ch     chan time.Time
+	fn     func()
+}
+
+type fakeTicker struct {
+	clock  *Fake
+	waiter *fakeWaiter
+}
+
+type fakeTimer struct {
+	clock *Fake
+	id    uint64
+}
+
+// NewFake - Tạo đồng hồ giả bắt đầu tại start
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start, waiters: make(map[uint64]*fakeWaiter)}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	waiter := &fakeWaiter{period: d, ch: make(chan time.Time, 1)}
+	f.add(waiter, d)
+	return fakeTicker{clock: f, waiter: waiter}
+}
+
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	waiter := &fakeWaiter{fn: fn}
+	f.add(waiter, d)
+	return fakeTimer{clock: f, id: waiter.id}
+}
+
+func (f *Fake) add(waiter *fakeWaiter, d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.nextID++
+	waiter.id = f.nextID
+	waiter.at = f.now.Add(d)
+	f.waiters[waiter.id] = waiter
+}
+
+// Advance - Tiến đồng hồ thêm d, kích hoạt lần lượt các ticker và timer tới hạn theo thứ tự thời gian.
+// Hàm của AfterFunc chạy ngay trong Advance; ticker bỏ qua tick nếu tick trước chưa được đọc như time.Ticker.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	end := f.now.Add(d)
+	f.mu.Unlock()
+
+	for {
+		f.mu.Lock()
+		waiter := f.nextDue(end)
+		if waiter == nil {
+			f.now = end
+			f.mu.Unlock()
+			return
+		}
+		f.now = waiter.at
+		if waiter.period > 0 {
+			waiter.at = waiter.at.Add(waiter.period)
+		} else {
+			delete(f.waiters, waiter.id)
+		}
+		now := f.now
+		f.mu.Unlock()
+
+		if waiter.fn != nil {
+			waiter.fn()
+			continue
+		}
+		select {
+		case waiter.ch <- now:
+		default:
+		}
+	}
+}
+
+// nextDue - Ticker hoặc timer tới hạn sớm nhất không muộn hơn end (cần giữ f.mu)
+func (f *Fake) nextDue(end time.Time) *fakeWaiter {
+	due := make([]*fakeWaiter, 0)
+	for _, waiter := range f.waiters {
+		if !waiter.at.After(end) {
+			due = append(due, waiter)
+		}
+	}
+	if len(due) == 0 {
+		return nil
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].at.Equal(due[j].at) {
+			return due[i].id < due[j].id
+		}
+		return due[i].at.Before(due[j].at)
+	})
+	return due[0]
+}
+
+// Waiters - Số ticker và timer đang chờ, giúp kiểm thử biết routine đã đăng ký xong
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+func (f *Fake) remove(id uint64) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, exists := f.waiters[id]
+	delete(f.waiters, id)
+	return exists
+}
+
+func (t fakeTicker) C() <-chan time.Time {
+	return t.waiter.ch
+}
+
+func (t fakeTicker) Stop() {
+	t.clock.remove(t.waiter.id)
+}
+
+func (t fakeTimer) Stop() bool {
+	return t.clock.remove(t.id)
+}
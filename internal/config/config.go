@@ -0,0 +1,197 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+)
+
+// Config - Cấu hình server, mặc định lấy từ constants và có thể ghi đè bằng file hoặc biến môi trường
+type Config struct {
+	World   WorldConfig   `json:"world"`
+	Player  PlayerConfig  `json:"player"`
+	Network NetworkConfig `json:"network"`
+}
+
+// WorldConfig - Kích thước world và lịch spawn Pokemon (thời gian tính bằng giây)
+type WorldConfig struct {
+	Width         int  `json:"width"`
+	Height        int  `json:"height"`
+	WrapAround    bool `json:"wrap_around"`
+	SpawnInterval int  `json:"spawn_interval"`
+	SpawnCount    int  `json:"spawn_count"`
+	DespawnTime   int  `json:"despawn_time"`
+
+	// Địa hình: đọc từ TerrainMap nếu có, không thì sinh từ TerrainSeed (0 là không có địa hình)
+	TerrainMap  string `json:"terrain_map"`
+	TerrainSeed int    `json:"terrain_seed"`
+
+	// Gặp Pokemon hoang dã: bước vào ô có Pokemon sẽ bắt đầu battle theo thể thức EncounterFormat
+	WildEncounters  bool   `json:"wild_encounters"`
+	EncounterFormat string `json:"encounter_format"`
+}
+
+// PlayerConfig - Giới hạn của player
+type PlayerConfig struct {
+	MaxInventory int `json:"max_inventory"`
+}
+
+// NetworkConfig - Cổng và timeout của TCP server (timeout tính bằng giây)
+type NetworkConfig struct {
+	TCPPort        int `json:"tcp_port"`
+	MaxConnections int `json:"max_connections"`
+	ReadTimeout    int `json:"read_timeout"`
+	WriteTimeout   int `json:"write_timeout"`
+}
+
+// Default - Cấu hình mặc định theo constants
+func Default() Config {
+	return Config{
+		World: WorldConfig{
+			Width:         constants.WorldWidth,
+			Height:        constants.WorldHeight,
+			WrapAround:    constants.WorldWrapAround,
+			SpawnInterval: constants.SpawnInterval,
+			SpawnCount:    constants.SpawnCount,
+			DespawnTime:   constants.DespawnTime,
+			TerrainSeed:   constants.TerrainSeed,
+
+			WildEncounters:  constants.WildEncounters,
+			EncounterFormat: constants.EncounterFormat,
+		},
+		Player: PlayerConfig{
+			MaxInventory: constants.MaxPokemonInventory,
+		},
+		Network: NetworkConfig{
+			TCPPort:        constants.TCPPort,
+			MaxConnections: constants.MaxConnections,
+			ReadTimeout:    constants.ReadTimeout,
+			WriteTimeout:   constants.WriteTimeout,
+		},
+	}
+}
+
+// Load - Đọc config theo thứ tự: mặc định, file path (bỏ qua nếu không tồn tại hoặc rỗng),
+// biến môi trường POKECAT_*, rồi kiểm tra tính hợp lệ
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return cfg, fmt.Errorf("failed to read config: %v", err)
+	}
+	if len(strings.TrimSpace(string(data))) > 0 {
+		// Chỉ các trường có trong file bị ghi đè, còn lại giữ mặc định
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("failed to parse config %s: %v", path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return cfg, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// LoadDefault - Đọc config từ file chỉ định bởi POKECAT_CONFIG, không có thì dùng ConfigPath
+func LoadDefault() (Config, error) {
+	path := constants.ConfigPath
+	if envPath, ok := os.LookupEnv(constants.ConfigEnvPath); ok && envPath != "" {
+		path = envPath
+	}
+	return Load(path)
+}
+
+// applyEnv - Ghi đè từng trường bằng biến môi trường, ví dụ POKECAT_WORLD_WIDTH=500
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	ints := map[string]*int{
+		"WORLD_WIDTH":             &c.World.Width,
+		"WORLD_HEIGHT":            &c.World.Height,
+		"WORLD_SPAWN_INTERVAL":    &c.World.SpawnInterval,
+		"WORLD_SPAWN_COUNT":       &c.World.SpawnCount,
+		"WORLD_DESPAWN_TIME":      &c.World.DespawnTime,
+		"WORLD_TERRAIN_SEED":      &c.World.TerrainSeed,
+		"PLAYER_MAX_INVENTORY":    &c.Player.MaxInventory,
+		"NETWORK_TCP_PORT":        &c.Network.TCPPort,
+		"NETWORK_MAX_CONNECTIONS": &c.Network.MaxConnections,
+		"NETWORK_READ_TIMEOUT":    &c.Network.ReadTimeout,
+		"NETWORK_WRITE_TIMEOUT":   &c.Network.WriteTimeout,
+	}
+	for name, field := range ints {
+		value, ok := lookup(constants.ConfigEnvPrefix + name)
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("invalid %s%s: %q", constants.ConfigEnvPrefix, name, value)
+		}
+		*field = parsed
+	}
+
+	bools := map[string]*bool{
+		"WORLD_WRAP_AROUND":     &c.World.WrapAround,
+		"WORLD_WILD_ENCOUNTERS": &c.World.WildEncounters,
+	}
+	for name, field := range bools {
+		value, ok := lookup(constants.ConfigEnvPrefix + name)
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("invalid %s%s: %q", constants.ConfigEnvPrefix, name, value)
+		}
+		*field = parsed
+	}
+
+	if value, ok := lookup(constants.ConfigEnvPrefix + "WORLD_TERRAIN_MAP"); ok {
+		c.World.TerrainMap = strings.TrimSpace(value)
+	}
+	if value, ok := lookup(constants.ConfigEnvPrefix + "WORLD_ENCOUNTER_FORMAT"); ok {
+		c.World.EncounterFormat = strings.TrimSpace(value)
+	}
+	return nil
+}
+
+// Validate - Kiểm tra các giá trị config nằm trong khoảng hợp lệ
+func (c Config) Validate() error {
+	checks := []struct {
+		name     string
+		value    int
+		min, max int
+	}{
+		{"world.width", c.World.Width, 1, constants.MaxWorldSize},
+		{"world.height", c.World.Height, 1, constants.MaxWorldSize},
+		{"world.spawn_interval", c.World.SpawnInterval, 1, 0},
+		{"world.spawn_count", c.World.SpawnCount, 0, 0},
+		{"world.despawn_time", c.World.DespawnTime, 1, 0},
+		{"world.terrain_seed", c.World.TerrainSeed, 0, 0},
+		{"player.max_inventory", c.Player.MaxInventory, 1, 0},
+		{"network.tcp_port", c.Network.TCPPort, 1, constants.MaxPort},
+		{"network.max_connections", c.Network.MaxConnections, 1, 0},
+		{"network.read_timeout", c.Network.ReadTimeout, 1, 0},
+		{"network.write_timeout", c.Network.WriteTimeout, 1, 0},
+	}
+	for _, check := range checks {
+		if check.value < check.min || (check.max > 0 && check.value > check.max) {
+			if check.max > 0 {
+				return fmt.Errorf("invalid config %s: %d (expected %d to %d)", check.name, check.value, check.min, check.max)
+			}
+			return fmt.Errorf("invalid config %s: %d (expected at least %d)", check.name, check.value, check.min)
+		}
+	}
+	// Tên thể thức được kiểm tra khi bật chế độ gặp Pokemon hoang dã
+	if c.World.WildEncounters && c.World.EncounterFormat == "" {
+		return fmt.Errorf("invalid config world.encounter_format: required when wild_encounters is enabled")
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+)
+
+func TestLoad(t *testing.T) {
+	empty := "  \n"
+	broken := `{"world": `
+	partial := `{"world": {"width": 50, "wrap_around": false}, "network": {"tcp_port": 9000}}`
+
+	tests := []struct {
+		name    string
+		file    *string // nil là không có file
+		env     map[string]string
+		want    func(cfg *Config) // Thay đổi so với mặc định
+		wantErr string
+	}{
+		{name: "missing file uses defaults", want: func(cfg *Config) {}},
+		{name: "empty file uses defaults", file: &empty, want: func(cfg *Config) {}},
+		{name: "partial file overrides its fields only", file: &partial, want: func(cfg *Config) {
+			cfg.World.Width = 50
+			cfg.World.WrapAround = false
+			cfg.Network.TCPPort = 9000
+		}},
+		{name: "env overrides file", file: &partial,
+			env: map[string]string{"WORLD_WIDTH": "70", "WORLD_WRAP_AROUND": "true", "PLAYER_MAX_INVENTORY": " 12 "},
+			want: func(cfg *Config) {
+				cfg.World.Width = 70
+				cfg.World.WrapAround = true
+				cfg.Network.TCPPort = 9000
+				cfg.Player.MaxInventory = 12
+			}},
+		{name: "bad int in env", env: map[string]string{"WORLD_HEIGHT": "tall"}, wantErr: "POKECAT_WORLD_HEIGHT"},
+		{name: "bad bool in env", env: map[string]string{"WORLD_WRAP_AROUND": "sometimes"}, wantErr: "POKECAT_WORLD_WRAP_AROUND"},
+		{name: "zero width", env: map[string]string{"WORLD_WIDTH": "0"}, wantErr: "world.width"},
+		{name: "width above max", env: map[string]string{"WORLD_WIDTH": "100000"}, wantErr: "world.width"},
+		{name: "port above max", env: map[string]string{"NETWORK_TCP_PORT": "70000"}, wantErr: "network.tcp_port"},
+		{name: "bad json", file: &broken, wantErr: "failed to parse config"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if test.file != nil {
+				if err := os.WriteFile(path, []byte(*test.file), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for name, value := range test.env {
+				t.Setenv(constants.ConfigEnvPrefix+name, value)
+			}
+
+			cfg, err := Load(path)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("Load error = %v, want error mentioning %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := Default()
+			test.want(&want)
+			if cfg != want {
+				t.Errorf("Load = %+v, want %+v", cfg, want)
+			}
+		})
+	}
+}
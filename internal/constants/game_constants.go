@@ -7,13 +7,43 @@ const (
 	SpawnInterval = 60  // Spawn pokemon mỗi 60 giây
 	DespawnTime   = 300 // Pokemon tự động despawn sau 5 phút
 	SpawnCount    = 50  // Số lượng pokemon spawn mỗi đợt
+
+	WorldWrapAround = true // Đi qua mép world sẽ sang mép đối diện, false thì bị chặn lại
+
+	WorldChunkSize       = 32 // World được chia thành chunk 32x32 ô, chỉ tạo khi có người hoặc Pokemon
+	ChunkReclaimInterval = 30 // Thu hồi ô và chunk trống mỗi 30 giây
+	DespawnCheckInterval = 1  // Kiểm tra Pokemon hết hạn mỗi giây
+
+	DefaultViewRadius = 10 // Vùng nhìn thấy mặc định: 10 ô mỗi phía quanh player
+	MaxViewRadius     = 32 // Bán kính vùng nhìn thấy tối đa client được yêu cầu
+
+	WorldEventBufferSize = 256 // Buffer event world mặc định cho mỗi subscriber
+
+	TerrainSeed           = 0  // Seed sinh địa hình, 0 là không có địa hình (spawn đều khắp world)
+	BiomeInfluenceRadius  = 2  // Nước, núi trong phạm vi 2 ô ảnh hưởng tới loài spawn ở ô bên cạnh
+	SpawnPositionAttempts = 10 // Số lần chọn lại vị trí spawn khi rơi vào địa hình không đi được
+	TerrainRelocateRadius = 64 // Bán kính tìm ô đi được gần nhất cho player đứng trên địa hình chặn
 )
 
 // Player Constants
 const (
 	MaxPokemonInventory = 200 // Số lượng pokemon tối đa một player có thể sở hữu
 	MovementSpeed       = 1   // Di chuyển 1 ô mỗi giây
-	MaxBattlePokemon    = 3   // Số pokemon tối đa cho mỗi trận đấu
+	MaxBattlePokemon    = 3   // Số pokemon mỗi bên ở thể thức chuẩn
+	MaxBattleTeamSize   = 6   // Số pokemon tối đa một team có thể chọn
+	MaxActivePokemon    = 2   // Số pokemon ra trận cùng lúc mỗi bên ở battle đôi
+)
+
+// Auto Mode Constants
+const (
+	DefaultAutoModeDuration = 120  // Thời gian tự động di chuyển mặc định (giây)
+	MaxAutoModeDuration     = 3600 // Thời gian tự động di chuyển tối đa (giây)
+	AutoSweepWidth          = 10   // Số ô đi ngang mỗi hàng của chiến lược quét
+	AutoVisionRadius        = 5    // Bán kính tìm Pokemon của chiến lược nearest_spawn
+
+	AutoStrategyRandom  = "random"        // Đi ngẫu nhiên
+	AutoStrategySweep   = "sweep"         // Quét từng hàng theo hình zigzag
+	AutoStrategyNearest = "nearest_spawn" // Đi về Pokemon gần nhất trong tầm nhìn
 )
 
 // Pokemon Stats Constants
@@ -34,6 +64,78 @@ const (
 	NormalAttackType  = "normal"  // Loại tấn công thường
 	SpecialAttackType = "special" // Loại tấn công đặc biệt
 	BattleTimeout     = 300       // Thời gian tối đa cho một trận đấu (giây)
+	TurnTimeout       = 30        // Thời gian tối đa cho mỗi lượt (giây)
+	MaxTurnTimeouts   = 3         // Số lần hết giờ liên tiếp trước khi xử thua
+	MinDamage         = 1         // Sát thương tối thiểu của một đòn đánh
+	EventBufferSize   = 64        // Kích thước buffer event cho mỗi subscriber
+	MaxSpectators     = 50        // Số người xem tối đa của một battle
+)
+
+// Damage Rule Constants
+const (
+	CriticalChance     = 0.0625 // Xác suất chí mạng (1/16)
+	CriticalMultiplier = 1.5    // Hệ số sát thương khi chí mạng
+	MoveAccuracy       = 0.9    // Xác suất đòn đánh trúng
+	SameTypeMultiplier = 1.5    // Hệ số khi type của đòn trùng type của Pokemon
+	MinDamageVariance  = 0.85   // Sát thương dao động ngẫu nhiên từ 85% đến 100%
+	NormalMoveType     = "Normal"
+)
+
+// Move Constants
+const (
+	MaxMoves             = 4          // Số đòn tối đa một Pokemon nhớ được
+	BaseMovePower        = 60         // Sức mạnh đòn chuẩn - đòn có power này gây sát thương như đòn generic
+	PhysicalMoveCategory = "physical" // Đòn vật lý dùng Attack/Defense
+	SpecialMoveCategory  = "special"  // Đòn đặc biệt dùng Sp. Atk/Sp. Def
+)
+
+// Status Condition Constants
+const (
+	StatusInflictChance  = 0.3  // Xác suất đòn đặc biệt cùng type gây trạng thái
+	ParalysisSpeedFactor = 0.5  // Tê liệt làm giảm một nửa tốc độ
+	ParalysisSkipChance  = 0.25 // Xác suất bị tê liệt không hành động được
+	MaxSleepTurns        = 3    // Số lượt ngủ tối đa
+	BurnDamageDivisor    = 16   // Bỏng mất 1/16 HP tối đa mỗi lượt
+	PoisonDamageDivisor  = 8    // Trúng độc mất 1/8 HP tối đa mỗi lượt
+)
+
+// Matchmaking Constants
+const (
+	ChallengeTimeout        = 60   // Lời thách đấu hết hạn sau 60 giây
+	ReadyTimeout            = 60   // Thời gian chờ hai bên sẵn sàng (giây)
+	MatchmakingInterval     = 2    // Chu kỳ ghép cặp và dọn dẹp battle (giây)
+	QueueBaseTolerance      = 0.10 // Chênh lệch sức mạnh team cho phép ban đầu (10%)
+	QueueToleranceGrowth    = 0.01 // Mức nới rộng mỗi giây chờ trong hàng đợi
+	QueueMaxTolerance       = 0.50 // Chênh lệch tối đa cho phép
+	FinishedBattleRetention = 30   // Giữ battle đã kết thúc 30 giây trước khi xóa
+	QueueNPCFillDelay       = 30   // Chờ quá 30 giây sẽ được ghép với NPC
+)
+
+// Wild Encounter Constants
+const (
+	WildPlayerPrefix       = "wild-" // ID phía Pokemon hoang dã trong battle
+	WildCaptureBaseChance  = 0.2     // Xác suất bắt khi Pokemon hoang dã còn đầy HP
+	WildCaptureWeakenBonus = 0.6     // Xác suất cộng thêm theo tỉ lệ HP đã mất
+	WildEncounters         = false   // Mặc định bước vào ô có Pokemon sẽ tự động bắt, không bắt đầu battle
+	EncounterFormat        = "standard"
+	EncounterDespawnGrace  = 30 // Pokemon đang trong battle hoang dã khi hết hạn được gia hạn thêm 30 giây
+)
+
+// Rating Constants
+const (
+	InitialRating      = 1000 // Rating ban đầu của player
+	EloKFactor         = 32   // Hệ số K của Elo
+	MaxMatchHistory    = 50   // Số trận gần nhất lưu trong hồ sơ player
+	DefaultLeaderboard = 10   // Số dòng mặc định của bảng xếp hạng
+	MaxLeaderboard     = 100  // Số dòng tối đa của bảng xếp hạng
+)
+
+// Tournament Constants
+const (
+	MinTournamentPlayers = 2   // Số người tối thiểu để bắt đầu giải
+	MaxTournamentPlayers = 64  // Số người tối đa của một giải
+	TournamentInterval   = 2   // Chu kỳ kiểm tra trận đấu của giải (giây)
+	MatchShowUpGrace     = 120 // Thời gian chờ player có mặt trước khi xử thua (giây)
 )
 
 // TypeEffectiveness - Bảng tương khắc chính thức giữa các type
@@ -203,15 +305,28 @@ const (
 
 // Error Messages
 const (
-	ErrInventoryFull     = "pokemon inventory is full"
-	ErrPokemonNotFound   = "pokemon not found"
-	ErrInvalidMove       = "invalid movement"
-	ErrBattleInProgress  = "battle already in progress"
-	ErrInvalidBattleTeam = "invalid battle team selection"
-	ErrInvalidLevel      = "invalid pokemon level"
-	ErrInvalidExp        = "invalid experience points"
-	ErrPokemonDestroyed  = "pokemon has been destroyed"
-	ErrTypeMismatch      = "pokemon types do not match for exp transfer"
+	ErrInventoryFull      = "pokemon inventory is full"
+	ErrPokemonNotFound    = "pokemon not found"
+	ErrInvalidMove        = "invalid movement"
+	ErrBattleInProgress   = "battle already in progress"
+	ErrInvalidBattleTeam  = "invalid battle team selection"
+	ErrInvalidLevel       = "invalid pokemon level"
+	ErrInvalidExp         = "invalid experience points"
+	ErrPokemonDestroyed   = "pokemon has been destroyed"
+	ErrTypeMismatch       = "pokemon types do not match for exp transfer"
+	ErrBattleNotFound     = "battle not found"
+	ErrChallengeNotFound  = "challenge not found"
+	ErrAlreadyQueued      = "player already in matchmaking queue"
+	ErrSpectatorLimit     = "battle has reached the spectator limit"
+	ErrBattleFinished     = "battle has already finished"
+	ErrTournamentNotFound = "tournament not found"
+	ErrTournamentStarted  = "tournament has already started"
+	ErrTournamentFull     = "tournament is full"
+	ErrAlreadyRegistered  = "player already registered in tournament"
+	ErrUnknownMove        = "pokemon does not know this move"
+	ErrNoPPLeft           = "move has no PP left"
+	ErrPokemonLocked      = "pokemon is already in an encounter"
+	ErrImpassableTerrain  = "terrain is impassable"
 )
 
 // Game States
@@ -233,6 +348,15 @@ const (
 	BattleStateFinished
 )
 
+// Battle End Reasons
+const (
+	EndReasonKO        = "ko"        // Hết Pokemon còn sống
+	EndReasonSurrender = "surrender" // Player đầu hàng
+	EndReasonTimeout   = "timeout"   // Hết giờ quá nhiều lần liên tiếp
+	EndReasonExpired   = "expired"   // Quá thời gian battle, không có người thắng
+	EndReasonCapture   = "capture"   // Bắt được Pokemon hoang dã
+)
+
 // Movement Directions
 type Direction int
 
@@ -246,5 +370,18 @@ const (
 // File Paths
 const (
 	PokedexPath        = "data/pokedex.json"
+	MovesPath          = "data/moves.json"
+	NPCRosterPath      = "data/npcs.json"
 	PlayerInventoryDir = "data/players/"
+	ReplayDir          = "data/replays/"
+	TournamentDir      = "data/tournaments/"
+	ConfigPath         = "configs/config.json"
+)
+
+// Config Constants
+const (
+	ConfigEnvPath   = "POKECAT_CONFIG" // Biến môi trường chỉ định file config khác ConfigPath
+	ConfigEnvPrefix = "POKECAT_"       // Tiền tố biến môi trường ghi đè config, ví dụ POKECAT_WORLD_WIDTH
+	MaxWorldSize    = 10000            // Kích thước tối đa mỗi chiều của world
+	MaxPort         = 65535
 )
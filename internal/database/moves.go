@@ -0,0 +1,161 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+)
+
+// MoveEntry - Dữ liệu một đòn đánh
+type MoveEntry struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Category string `json:"category"` // physical hoặc special
+	Power    int    `json:"power"`
+	Accuracy int    `json:"accuracy"` // Độ chính xác theo phần trăm
+	PP       int    `json:"pp"`
+}
+
+// LearnsetEntry - Loài học được đòn Move khi đạt Level
+type LearnsetEntry struct {
+	Level int    `json:"level"`
+	Move  string `json:"move"`
+}
+
+// moveFile - Cấu trúc file moves.json
+type moveFile struct {
+	Moves     []MoveEntry                `json:"moves"`
+	Learnsets map[string][]LearnsetEntry `json:"learnsets"` // Theo tên loài
+}
+
+// MoveDex - Dữ liệu đòn đánh và learnset của từng loài. Tách khỏi pokedex.json vì Pokedex là dữ liệu
+// thu thập từ pokemondb.net và có thể được tải lại, còn đòn đánh và learnset là dữ liệu cân bằng của game.
+// Các loài cùng nhánh tiến hóa dùng chung learnset của dạng đầu, dạng sau chỉ thêm đòn theo hệ mới.
+type MoveDex struct {
+	Moves     []MoveEntry
+	byName    map[string]int
+	learnsets map[string][]LearnsetEntry
+}
+
+var (
+	defaultMoveDex     *MoveDex
+	defaultMoveDexErr  error
+	defaultMoveDexOnce sync.Once
+)
+
+// GetMoveDex - Lấy dữ liệu đòn đánh mặc định, chỉ đọc file một lần
+func GetMoveDex() (*MoveDex, error) {
+	defaultMoveDexOnce.Do(func() {
+		defaultMoveDex, defaultMoveDexErr = LoadMoveDex(constants.MovesPath)
+	})
+	return defaultMoveDex, defaultMoveDexErr
+}
+
+// LoadMoveDex - Đọc và kiểm tra file moves.json
+func LoadMoveDex(path string) (*MoveDex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read move data: %v", err)
+	}
+
+	var file moveFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse move data: %v", err)
+	}
+
+	dex := &MoveDex{
+		Moves:     make([]MoveEntry, 0, len(file.Moves)),
+		byName:    make(map[string]int),
+		learnsets: make(map[string][]LearnsetEntry),
+	}
+	for _, move := range file.Moves {
+		if err := move.validate(); err != nil {
+			return nil, fmt.Errorf("invalid move %s: %v", move.Name, err)
+		}
+		key := strings.ToLower(move.Name)
+		if _, exists := dex.byName[key]; exists {
+			return nil, fmt.Errorf("duplicate move: %s", move.Name)
+		}
+		dex.byName[key] = len(dex.Moves)
+		dex.Moves = append(dex.Moves, move)
+	}
+
+	for species, learnset := range file.Learnsets {
+		for _, entry := range learnset {
+			if _, exists := dex.byName[strings.ToLower(entry.Move)]; !exists {
+				return nil, fmt.Errorf("learnset of %s: unknown move %s", species, entry.Move)
+			}
+			if entry.Level < 1 || entry.Level > constants.MaxLevel {
+				return nil, fmt.Errorf("learnset of %s: invalid level %d", species, entry.Level)
+			}
+		}
+		sorted := append([]LearnsetEntry(nil), learnset...)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Level < sorted[j].Level })
+		dex.learnsets[strings.ToLower(species)] = sorted
+	}
+
+	if len(dex.Moves) == 0 {
+		return nil, fmt.Errorf("no move data available")
+	}
+	return dex, nil
+}
+
+func (m MoveEntry) validate() error {
+	if m.Name == "" || m.Type == "" {
+		return fmt.Errorf("missing name or type")
+	}
+	if m.Category != constants.PhysicalMoveCategory && m.Category != constants.SpecialMoveCategory {
+		return fmt.Errorf("invalid category %q", m.Category)
+	}
+	if m.Power <= 0 || m.PP <= 0 || m.Accuracy <= 0 || m.Accuracy > 100 {
+		return fmt.Errorf("invalid power, accuracy or pp")
+	}
+	return nil
+}
+
+// Find - Tìm đòn đánh theo tên (không phân biệt hoa thường)
+func (d *MoveDex) Find(name string) (*MoveEntry, error) {
+	index, exists := d.byName[strings.ToLower(strings.TrimSpace(name))]
+	if !exists {
+		return nil, fmt.Errorf("move %q not found", name)
+	}
+	return &d.Moves[index], nil
+}
+
+// Learnset - Các đòn loài học được theo level tăng dần
+func (d *MoveDex) Learnset(species string) []LearnsetEntry {
+	return d.learnsets[strings.ToLower(species)]
+}
+
+// KnownMoves - Các đòn Pokemon ở level này nhớ được: tối đa MaxMoves đòn học gần nhất
+func (d *MoveDex) KnownMoves(species string, level int) []MoveEntry {
+	var known []MoveEntry
+	for _, entry := range d.Learnset(species) {
+		if entry.Level > level {
+			break
+		}
+		move, _ := d.Find(entry.Move)
+		known = append(known, *move)
+	}
+	if len(known) > constants.MaxMoves {
+		known = known[len(known)-constants.MaxMoves:]
+	}
+	return known
+}
+
+// MovesLearnedAt - Các đòn loài học được đúng ở level này
+func (d *MoveDex) MovesLearnedAt(species string, level int) []MoveEntry {
+	var learned []MoveEntry
+	for _, entry := range d.Learnset(species) {
+		if entry.Level == level {
+			move, _ := d.Find(entry.Move)
+			learned = append(learned, *move)
+		}
+	}
+	return learned
+}
@@ -0,0 +1,231 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/random"
+)
+
+// rawEntry - Một dòng trong pokedex.json (crawler lưu mọi giá trị dạng chuỗi)
+type rawEntry struct {
+	FullName   string `json:"full_name"`
+	Name       string `json:"name"`
+	Number     string `json:"number"`
+	Type       string `json:"type"`
+	Total      string `json:"total"`
+	HP         string `json:"hp"`
+	Attack     string `json:"attack"`
+	Defense    string `json:"defense"`
+	SpAtk      string `json:"sp_atk"`
+	SpDef      string `json:"sp_def"`
+	Speed      string `json:"speed"`
+	DetailPath string `json:"detail_path"`
+	BaseExp    string `json:"base_exp"`
+}
+
+// PokedexEntry - Dữ liệu một loài Pokemon đã được parse
+type PokedexEntry struct {
+	FullName   string
+	Name       string
+	Number     string
+	Types      []string
+	HP         int
+	Attack     int
+	Defense    int
+	SpAtk      int
+	SpDef      int
+	Speed      int
+	Total      int
+	BaseExp    int
+	DetailPath string
+}
+
+// Pokedex - Toàn bộ dữ liệu Pokedex, tra cứu theo tên hoặc số
+type Pokedex struct {
+	Entries []PokedexEntry
+	byName  map[string]int
+}
+
+var (
+	defaultPokedex *Pokedex
+	defaultErr     error
+	defaultOnce    sync.Once
+)
+
+// GetPokedex - Lấy Pokedex mặc định, chỉ đọc file một lần
+func GetPokedex() (*Pokedex, error) {
+	defaultOnce.Do(func() {
+		defaultPokedex, defaultErr = LoadPokedex(constants.PokedexPath)
+	})
+	return defaultPokedex, defaultErr
+}
+
+// LoadPokedex - Đọc và parse file pokedex.json
+func LoadPokedex(path string) (*Pokedex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pokedex: %v", err)
+	}
+
+	var rawEntries []rawEntry
+	if err := json.Unmarshal(data, &rawEntries); err != nil {
+		return nil, fmt.Errorf("failed to parse pokedex data: %v", err)
+	}
+
+	pokedex := &Pokedex{
+		Entries: make([]PokedexEntry, 0, len(rawEntries)),
+		byName:  make(map[string]int),
+	}
+	for _, raw := range rawEntries {
+		entry, err := raw.parse()
+		if err != nil {
+			return nil, fmt.Errorf("invalid pokedex entry %s: %v", raw.FullName, err)
+		}
+		pokedex.add(entry)
+	}
+
+	if len(pokedex.Entries) == 0 {
+		return nil, fmt.Errorf("no pokemon data available")
+	}
+	return pokedex, nil
+}
+
+func (p *Pokedex) add(entry PokedexEntry) {
+	index := len(p.Entries)
+	p.Entries = append(p.Entries, entry)
+
+	// Tên hiển thị luôn duy nhất; tên gốc, tên dạng và số trỏ tới dạng xuất hiện đầu tiên
+	p.byName[strings.ToLower(entry.DisplayName())] = index
+	for _, key := range []string{strings.ToLower(entry.FullName), strings.ToLower(entry.Name), entry.Number} {
+		if _, exists := p.byName[key]; !exists {
+			p.byName[key] = index
+		}
+	}
+}
+
+// Find - Tìm Pokemon theo tên hiển thị, tên đầy đủ, tên hoặc số Pokedex
+func (p *Pokedex) Find(name string) (*PokedexEntry, error) {
+	index, exists := p.byName[strings.ToLower(strings.TrimSpace(name))]
+	if !exists {
+		return nil, fmt.Errorf("pokemon %q not found in pokedex", name)
+	}
+	return &p.Entries[index], nil
+}
+
+// Random - Chọn ngẫu nhiên một loài (rng nil sẽ dùng nguồn ngẫu nhiên toàn cục)
+func (p *Pokedex) Random(rng random.Source) *PokedexEntry {
+	if rng == nil {
+		return &p.Entries[rand.Intn(len(p.Entries))]
+	}
+	return &p.Entries[rng.Intn(len(p.Entries))]
+}
+
+// RandomWeighted - Chọn ngẫu nhiên một loài với xác suất tỉ lệ theo weight(entry).
+// Loài có trọng số không dương không bao giờ được chọn; mọi trọng số bằng 0 thì chọn đều.
+func (p *Pokedex) RandomWeighted(rng random.Source, weight func(*PokedexEntry) float64) *PokedexEntry {
+	if weight == nil {
+		return p.Random(rng)
+	}
+	weights := make([]float64, len(p.Entries))
+	total := 0.0
+	for i := range p.Entries {
+		weights[i] = max(weight(&p.Entries[i]), 0)
+		total += weights[i]
+	}
+	if total == 0 {
+		return p.Random(rng)
+	}
+
+	roll := rand.Float64() * total
+	if rng != nil {
+		roll = rng.Float64() * total
+	}
+	for i, w := range weights {
+		if roll < w {
+			return &p.Entries[i]
+		}
+		roll -= w
+	}
+	// Sai số làm tròn: chọn loài cuối cùng có trọng số dương
+	for i := len(weights) - 1; i >= 0; i-- {
+		if weights[i] > 0 {
+			return &p.Entries[i]
+		}
+	}
+	return p.Random(rng)
+}
+
+// DisplayName - Tên phân biệt được các dạng, ví dụ "Giratina (Origin Forme)"
+func (e *PokedexEntry) DisplayName() string {
+	if e.FullName == "" || strings.Contains(e.FullName, e.Name) {
+		if e.FullName == "" {
+			return e.Name
+		}
+		return e.FullName
+	}
+	return fmt.Sprintf("%s (%s)", e.Name, e.FullName)
+}
+
+// ToMap - Chuyển entry sang dạng map mà models.NewPokemon sử dụng
+func (e *PokedexEntry) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"full_name": e.FullName,
+		"name":      e.Name,
+		"number":    e.Number,
+		"type":      strings.Join(e.Types, " "),
+		"hp":        float64(e.HP),
+		"attack":    float64(e.Attack),
+		"defense":   float64(e.Defense),
+		"sp_atk":    float64(e.SpAtk),
+		"sp_def":    float64(e.SpDef),
+		"speed":     float64(e.Speed),
+		"total":     float64(e.Total),
+		"base_exp":  float64(e.BaseExp),
+	}
+}
+
+func (r rawEntry) parse() (PokedexEntry, error) {
+	entry := PokedexEntry{
+		FullName:   r.FullName,
+		Name:       r.Name,
+		Number:     r.Number,
+		Types:      strings.Fields(r.Type),
+		DetailPath: r.DetailPath,
+	}
+	if len(entry.Types) == 0 {
+		return entry, fmt.Errorf("missing type")
+	}
+
+	stats := []struct {
+		value  string
+		target *int
+	}{
+		{r.HP, &entry.HP},
+		{r.Attack, &entry.Attack},
+		{r.Defense, &entry.Defense},
+		{r.SpAtk, &entry.SpAtk},
+		{r.SpDef, &entry.SpDef},
+		{r.Speed, &entry.Speed},
+		{r.Total, &entry.Total},
+	}
+	for _, stat := range stats {
+		value, err := strconv.Atoi(strings.TrimSpace(stat.value))
+		if err != nil {
+			return entry, fmt.Errorf("invalid stat %q", stat.value)
+		}
+		*stat.target = value
+	}
+
+	// Một số Pokemon mới chưa có base exp ("—")
+	if baseExp, err := strconv.Atoi(strings.TrimSpace(r.BaseExp)); err == nil {
+		entry.BaseExp = baseExp
+	}
+	return entry, nil
+}
@@ -0,0 +1,376 @@
+package pokebat
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/random"
+)
+
+type ActionKind string
+
+const (
+	ActionAttack ActionKind = "attack"
+	ActionSwitch ActionKind = "switch"
+)
+
+// Action - Hành động của một phía trong lượt: tấn công hoặc đổi Pokemon
+type Action struct {
+	Kind     ActionKind `json:"kind"`
+	Move     string     `json:"move,omitempty"`
+	Target   int        `json:"target,omitempty"` // Vị trí đối thủ bị nhắm tới (battle đôi), 0 là mặc định
+	SwitchTo int        `json:"switch_to,omitempty"`
+}
+
+// OpponentSlot - Pokemon đang ra trận của đối thủ ở một vị trí
+type OpponentSlot struct {
+	Position int // Vị trí (1, 2) ở battle đôi, 0 ở battle đơn
+	Pokemon  models.Pokemon
+	Status   StatusKind
+}
+
+// BattleView - Những gì một player nhìn thấy để ra quyết định
+type BattleView struct {
+	BattleID          string
+	PlayerID          string
+	OpponentID        string
+	Turn              int
+	IsMyTurn          bool
+	MustSwitch        bool
+	Team              []models.Pokemon
+	Status            []StatusCondition // Trạng thái của từng Pokemon trong team
+	ActiveIndex       int
+	ActiveSlots       []int          // Vị trí trong team của các Pokemon đang ra trận, -1 nếu bỏ trống
+	Opponent          models.Pokemon // Pokemon đang ra trận của đối thủ (mục tiêu mặc định)
+	Opponents         []OpponentSlot // Các Pokemon đang ra trận của đối thủ
+	OpponentRemaining int
+}
+
+// Active - Pokemon đang ra trận của player
+func (v BattleView) Active() models.Pokemon {
+	return v.Team[v.ActiveIndex]
+}
+
+// BattleAgent - Bộ não ra quyết định cho NPC trainer
+type BattleAgent interface {
+	ChooseAction(view BattleView) Action
+}
+
+// View - Tạo góc nhìn của player cho agent hoặc client
+func (b *Battle) View(playerID string) (BattleView, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if !b.HasPlayer(playerID) {
+		return BattleView{}, fmt.Errorf("invalid player ID")
+	}
+
+	self := b.getAttackingPlayer(playerID)
+	opponent := b.getDefendingPlayer(playerID)
+	view := BattleView{
+		BattleID:    b.ID,
+		PlayerID:    playerID,
+		OpponentID:  opponent.ID,
+		Turn:        b.Turn,
+		IsMyTurn:    b.State == BattleStateActive && b.waitingOn(playerID),
+		MustSwitch:  self.PendingSwitch,
+		Team:        make([]models.Pokemon, len(self.Team)),
+		Status:      append([]StatusCondition(nil), self.Status...),
+		ActiveIndex: self.CurrentIndex,
+		ActiveSlots: append([]int(nil), self.Active...),
+		Opponent:    *opponent.Team[opponent.CurrentIndex].Clone(),
+	}
+	if view.IsMyTurn && self.Active[b.CurrentSlot] >= 0 {
+		view.ActiveIndex = self.Active[b.CurrentSlot]
+	}
+	for i, pokemon := range self.Team {
+		view.Team[i] = *pokemon.Clone()
+	}
+	for slot := range opponent.Active {
+		if pokemon := opponent.activePokemon(slot); pokemon != nil && pokemon.IsAlive() {
+			view.Opponents = append(view.Opponents, OpponentSlot{
+				Position: b.position(slot),
+				Pokemon:  *pokemon.Clone(),
+				Status:   opponent.Status[opponent.Active[slot]].Status,
+			})
+		}
+	}
+	if len(view.Opponents) > 0 {
+		view.Opponent = view.Opponents[0].Pokemon
+	}
+	for _, pokemon := range opponent.Team {
+		if pokemon.IsAlive() {
+			view.OpponentRemaining++
+		}
+	}
+	return view, nil
+}
+
+// SubmitAction - Thực hiện hành động của player (dùng chung cho người và agent)
+func (b *Battle) SubmitAction(playerID string, action Action) error {
+	switch action.Kind {
+	case ActionAttack:
+		return b.ExecuteMoveAt(playerID, action.Move, action.Target)
+	case ActionSwitch:
+		return b.SwitchPokemon(playerID, action.SwitchTo)
+	}
+	return fmt.Errorf("invalid action kind: %s", action.Kind)
+}
+
+// RunAgent - Cho agent điều khiển một phía của battle qua luồng event như người chơi.
+// Trả về hàm dừng agent.
+func RunAgent(b *Battle, playerID string, agent BattleAgent) func() {
+	events, unsubscribe := b.Subscribe(constants.EventBufferSize)
+
+	go func() {
+		lastKey := ""
+		act := func() {
+			view, err := b.View(playerID)
+			if err != nil || !view.IsMyTurn {
+				return
+			}
+			// Mỗi trạng thái lượt chỉ hành động một lần
+			key := fmt.Sprintf("%d/%t/%d", view.Turn, view.MustSwitch, view.ActiveIndex)
+			if key == lastKey {
+				return
+			}
+			// Hành động bị từ chối thì thử lại ở event kế tiếp
+			if b.SubmitAction(playerID, agent.ChooseAction(view)) == nil {
+				lastKey = key
+			}
+		}
+
+		act()
+		for range events {
+			act()
+		}
+	}()
+
+	return unsubscribe
+}
+
+// PlayAgents - Chạy battle đồng bộ giữa hai agent (dùng cho mô phỏng và kiểm thử)
+func PlayAgents(b *Battle, agent1, agent2 BattleAgent, maxActions int) error {
+	b.mu.Lock()
+	b.timer.manual = true
+	b.stopTurnTimer()
+	b.mu.Unlock()
+
+	if b.GetState() == BattleStateWaiting {
+		b.SetPlayerReady(b.Player1.ID)
+		b.SetPlayerReady(b.Player2.ID)
+	}
+
+	agents := map[string]BattleAgent{b.Player1.ID: agent1, b.Player2.ID: agent2}
+	for i := 0; i < maxActions; i++ {
+		if b.GetState() != BattleStateActive {
+			return nil
+		}
+
+		// Mỗi bước cho một player đang được chờ hành động
+		acted := false
+		for _, playerID := range []string{b.Player1.ID, b.Player2.ID} {
+			view, err := b.View(playerID)
+			if err != nil {
+				return err
+			}
+			if !view.IsMyTurn {
+				continue
+			}
+			if err := b.SubmitAction(playerID, agents[playerID].ChooseAction(view)); err != nil {
+				return fmt.Errorf("agent %s: %v", playerID, err)
+			}
+			acted = true
+			break
+		}
+		if !acted {
+			return fmt.Errorf("no player can act")
+		}
+	}
+
+	b.mu.Lock()
+	b.expire()
+	b.mu.Unlock()
+	return fmt.Errorf("battle did not finish after %d actions", maxActions)
+}
+
+// NewAgent - Tạo agent theo tên: random, greedy hoặc lookahead
+func NewAgent(kind string, seed int64) (BattleAgent, error) {
+	switch kind {
+	case "random":
+		return NewRandomAgent(seed), nil
+	case "greedy":
+		return NewGreedyAgent(), nil
+	case "lookahead":
+		return NewLookaheadAgent(), nil
+	}
+	return nil, fmt.Errorf("unknown agent %q", kind)
+}
+
+// RandomAgent - Chọn ngẫu nhiên đòn đánh và Pokemon thay thế; dùng chung được giữa nhiều battle
+type RandomAgent struct {
+	rng random.Source
+}
+
+func NewRandomAgent(seed int64) *RandomAgent {
+	return &RandomAgent{rng: random.New(seed)}
+}
+
+func (a *RandomAgent) ChooseAction(view BattleView) Action {
+	if view.MustSwitch {
+		options := switchOptions(view)
+		return Action{Kind: ActionSwitch, SwitchTo: options[a.rng.Intn(len(options))]}
+	}
+	if moves := usableMoves(view.Active()); len(moves) > 0 {
+		return Action{Kind: ActionAttack, Move: moves[a.rng.Intn(len(moves))]}
+	}
+	if a.rng.Float64() < 0.5 {
+		return Action{Kind: ActionAttack, Move: constants.NormalAttackType}
+	}
+	return Action{Kind: ActionAttack, Move: constants.SpecialAttackType}
+}
+
+// GreedyAgent - Luôn chọn đòn gây sát thương kỳ vọng lớn nhất theo TypeEffectiveness
+type GreedyAgent struct{}
+
+func NewGreedyAgent() *GreedyAgent {
+	return &GreedyAgent{}
+}
+
+func (a *GreedyAgent) ChooseAction(view BattleView) Action {
+	if view.MustSwitch {
+		best, bestDamage := -1, -1
+		for _, i := range switchOptions(view) {
+			_, damage := bestMove(&view.Team[i], &view.Opponent)
+			if damage > bestDamage {
+				best, bestDamage = i, damage
+			}
+		}
+		return Action{Kind: ActionSwitch, SwitchTo: best}
+	}
+
+	// Nhắm vào đối thủ nhận sát thương lớn nhất
+	active := view.Active()
+	best, bestDamage := Action{Kind: ActionAttack}, -1
+	for _, opponent := range view.Opponents {
+		move, damage := bestMove(&active, &opponent.Pokemon)
+		if damage > bestDamage {
+			best, bestDamage = Action{Kind: ActionAttack, Move: move, Target: opponent.Position}, damage
+		}
+	}
+	if bestDamage < 0 {
+		best.Move, _ = bestMove(&active, &view.Opponent)
+	}
+	return best
+}
+
+// LookaheadAgent - Nhìn trước một lượt đối thủ để cân nhắc giữa đánh và đổi Pokemon
+type LookaheadAgent struct {
+	// SwitchPenalty - Điểm trừ cho việc mất lượt khi đổi Pokemon
+	SwitchPenalty float64
+}
+
+func NewLookaheadAgent() *LookaheadAgent {
+	return &LookaheadAgent{SwitchPenalty: 0.1}
+}
+
+func (a *LookaheadAgent) ChooseAction(view BattleView) Action {
+	opponent := view.Opponent
+
+	if view.MustSwitch {
+		best, bestScore := -1, math.Inf(-1)
+		for _, i := range switchOptions(view) {
+			score := matchupScore(&view.Team[i], &opponent)
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		return Action{Kind: ActionSwitch, SwitchTo: best}
+	}
+
+	// Ở lại và tấn công: gây sát thương ngay, nhận đòn đáp trả nếu đối thủ chưa bị hạ
+	active := view.Active()
+	move, damage := bestMove(&active, &opponent)
+	stayScore := hpFraction(damage, opponent.CurrentStats.HP)
+	if damage < opponent.CurrentStats.HP {
+		_, incoming := bestMove(&opponent, &active)
+		stayScore -= hpFraction(incoming, active.CurrentStats.HP)
+	} else {
+		stayScore += 1.0 // Hạ gục được ngay
+	}
+	best := Action{Kind: ActionAttack, Move: move}
+
+	// Đổi Pokemon: mất lượt, Pokemon mới nhận đòn trước rồi mới tấn công
+	for _, i := range switchOptions(view) {
+		score := matchupScore(&view.Team[i], &opponent) - a.SwitchPenalty
+		if score > stayScore {
+			stayScore = score
+			best = Action{Kind: ActionSwitch, SwitchTo: i}
+		}
+	}
+	return best
+}
+
+// matchupScore - Đánh giá một Pokemon khi đối đầu với Pokemon của đối thủ
+func matchupScore(pokemon, opponent *models.Pokemon) float64 {
+	_, outgoing := bestMove(pokemon, opponent)
+	_, incoming := bestMove(opponent, pokemon)
+	return hpFraction(outgoing, opponent.CurrentStats.HP) - hpFraction(incoming, pokemon.CurrentStats.HP)
+}
+
+// bestMove - Đòn đánh có sát thương kỳ vọng lớn nhất
+func bestMove(attacker, defender *models.Pokemon) (string, int) {
+	if moves := usableMoves(*attacker); len(moves) > 0 {
+		best, bestDamage := "", -1
+		for _, move := range moves {
+			if damage := EstimateDamage(attacker, defender, move); damage > bestDamage {
+				best, bestDamage = move, damage
+			}
+		}
+		return best, bestDamage
+	}
+
+	normal := EstimateDamage(attacker, defender, constants.NormalAttackType)
+	special := EstimateDamage(attacker, defender, constants.SpecialAttackType)
+	if special > normal {
+		return constants.SpecialAttackType, special
+	}
+	return constants.NormalAttackType, normal
+}
+
+// EstimateDamage - Sát thương kỳ vọng của một đòn đánh
+func EstimateDamage(attacker, defender *models.Pokemon, moveType string) int {
+	return computeDamage(attacker, defender, moveType).Damage
+}
+
+func hpFraction(damage, hp int) float64 {
+	if hp <= 0 {
+		return 1.0
+	}
+	return math.Min(float64(damage)/float64(hp), 1.0)
+}
+
+func switchOptions(view BattleView) []int {
+	options := make([]int, 0, len(view.Team))
+	for i := range view.Team {
+		if view.Team[i].IsAlive() && !view.isActive(i) {
+			options = append(options, i)
+		}
+	}
+	return options
+}
+
+// isActive - Pokemon thứ index đang ra trận
+func (v BattleView) isActive(index int) bool {
+	if index == v.ActiveIndex {
+		return true
+	}
+	for _, active := range v.ActiveSlots {
+		if active == index {
+			return true
+		}
+	}
+	return false
+}
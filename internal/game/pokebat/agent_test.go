@@ -0,0 +1,117 @@
+package pokebat
+
+import (
+	"testing"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/database"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// genericPokemon - Pokemon chưa học đòn nên chỉ dùng đòn thường và đòn đặc biệt theo type
+func genericPokemon(t *testing.T, name string, level int) *models.Pokemon {
+	t.Helper()
+	pokedex, err := database.GetPokedex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := pokedex.Find(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pokemon, err := models.NewPokemon(entry.ToMap(), level, 0.75)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pokemon
+}
+
+func TestGreedyAgentPicksMostDamagingAttack(t *testing.T) {
+	tests := []struct {
+		attacker, defender string
+		want               string
+	}{
+		// Fire đánh Grass gấp đôi nên đòn đặc biệt theo type mạnh hơn đòn thường
+		{"Charmander", "Bulbasaur", constants.SpecialAttackType},
+		{"Squirtle", "Charmander", constants.SpecialAttackType},
+		// Rock đánh Normal không có lợi thế, Geodude có công vật lý cao hơn nhiều
+		{"Geodude", "Rattata", constants.NormalAttackType},
+	}
+	for _, test := range tests {
+		attacker, defender := genericPokemon(t, test.attacker, 50), genericPokemon(t, test.defender, 50)
+		battle, err := NewBattleWithTeams("greedy-test", "p1", []*models.Pokemon{attacker},
+			"p2", []*models.Pokemon{defender}, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		view, err := battle.View("p1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		action := NewGreedyAgent().ChooseAction(view)
+		if action.Kind != ActionAttack || action.Move != test.want {
+			t.Errorf("%s vs %s: greedy chose %+v, want %s", test.attacker, test.defender, action, test.want)
+		}
+	}
+
+	if multiplier := constants.TypeEffectiveness["Fire"]["Grass"]; multiplier <= 1 {
+		t.Fatalf("Fire against Grass has multiplier %v, expected super effective", multiplier)
+	}
+}
+
+func TestLookaheadAgentSwitchesOutOfLosingMatchup(t *testing.T) {
+	tests := []struct {
+		name string
+		team []string
+		want Action
+	}{
+		// Charmander gần như không gây được sát thương cho Squirtle và bị hạ trong một đòn
+		{"losing", []string{"Charmander", "Pikachu"}, Action{Kind: ActionSwitch, SwitchTo: 1}},
+		{"winning", []string{"Pikachu", "Charmander"}, Action{Kind: ActionAttack, Move: constants.SpecialAttackType}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			team := make([]*models.Pokemon, len(test.team))
+			for i, name := range test.team {
+				team[i] = genericPokemon(t, name, 50)
+			}
+			battle, err := NewBattleWithTeams("lookahead-test", "p1", team,
+				"p2", []*models.Pokemon{genericPokemon(t, "Squirtle", 50)}, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			view, err := battle.View("p1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if action := NewLookaheadAgent().ChooseAction(view); action != test.want {
+				t.Errorf("lookahead chose %+v, want %+v", action, test.want)
+			}
+		})
+	}
+}
+
+// TestPlayAgentsSeededBattle - Battle giữa hai agent chạy tới khi kết thúc, cùng seed cho cùng kết quả
+func TestPlayAgentsSeededBattle(t *testing.T) {
+	play := func() *Battle {
+		battle, err := NewBattleWithTeams("agents-test",
+			"p1", newTestTeam(t, 30, "Bulbasaur", "Charmander", "Squirtle"),
+			"p2", newTestTeam(t, 30, "Pidgey", "Rattata", "Pikachu"), 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := PlayAgents(battle, NewRandomAgent(3), NewLookaheadAgent(), 1000); err != nil {
+			t.Fatal(err)
+		}
+		return battle
+	}
+
+	first, second := play(), play()
+	if first.GetState() != BattleStateFinished || first.GetWinner() == "" {
+		t.Fatalf("battle ended in state %v with winner %q", first.GetState(), first.GetWinner())
+	}
+	if first.GetWinner() != second.GetWinner() || len(first.Events) != len(second.Events) {
+		t.Errorf("same seed gave winner %s after %d events, then %s after %d events",
+			first.GetWinner(), len(first.Events), second.GetWinner(), len(second.Events))
+	}
+}
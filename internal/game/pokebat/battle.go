@@ -2,12 +2,13 @@ package pokebat
 
 import (
 	"fmt"
-	"math/rand"
 	"sync"
 	"time"
 
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/clock"
 	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
 	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/random"
 )
 
 type BattleState string
@@ -18,123 +19,253 @@ const (
 	BattleStateFinished BattleState = "finished"
 )
 
+// EndReason - Lý do battle kết thúc
+type EndReason string
+
+const (
+	EndReasonKO        EndReason = constants.EndReasonKO
+	EndReasonSurrender EndReason = constants.EndReasonSurrender
+	EndReasonTimeout   EndReason = constants.EndReasonTimeout
+	EndReasonExpired   EndReason = constants.EndReasonExpired
+	EndReasonCapture   EndReason = constants.EndReasonCapture
+)
+
 type BattlePlayer struct {
-	ID           string
-	CurrentIndex int
-	Team         []*models.Pokemon
-	IsReady      bool
-	HasSurrender bool
+	ID            string
+	CurrentIndex  int   // Pokemon ở vị trí ra trận đầu tiên
+	Active        []int // Vị trí trong team của Pokemon ở từng vị trí ra trận, -1 nếu bỏ trống
+	Team          []*models.Pokemon
+	IsReady       bool
+	HasSurrender  bool
+	PendingSwitch bool              // Pokemon vừa bị hạ, player phải chọn Pokemon thay thế
+	PendingSlots  []int             // Các vị trí ra trận đang chờ Pokemon thay thế
+	Status        []StatusCondition // Trạng thái của từng Pokemon trong team
+	maxHP         []int             // HP khi vào battle, dùng để tính sát thương trạng thái
 }
 
 type Battle struct {
-	ID           string
-	Player1      *BattlePlayer
-	Player2      *BattlePlayer
-	State        BattleState
-	CurrentTurn  string
-	LastMoveTime time.Time
-	StartTime    time.Time
-	Logs         []string
-	mu           sync.RWMutex
+	ID            string
+	Player1       *BattlePlayer
+	Player2       *BattlePlayer
+	State         BattleState
+	CurrentTurn   string
+	CurrentSlot   int // Vị trí ra trận đang hành động của CurrentTurn (battle đôi)
+	ActiveSlots   int // Số Pokemon ra trận cùng lúc mỗi bên
+	TurnModel     TurnModel
+	Turn          int
+	WinnerID      string
+	EndReason     EndReason
+	Format        string
+	Seed          int64
+	LastMoveTime  time.Time
+	StartTime     time.Time
+	EndTime       time.Time
+	Events        []Event
+	rng           random.Source
+	clock         clock.Clock
+	turnOrder     []actor           // Các vị trí chưa hành động trong vòng hiện tại (battle đôi)
+	submitted     map[string]Action // Hành động đã khóa trong lượt đánh đồng thời, ẩn với đối thủ
+	modifiers     []DamageModifier  // Các luật sát thương của thể thức
+	statusEnabled bool              // Thể thức có trạng thái bất lợi
+	wildID        string            // ID phía Pokemon hoang dã nếu là battle gặp Pokemon hoang dã
+	timer         turnTimer
+	recorder      replayRecorder
+	subscribers   map[int]chan Event
+	spectators    map[int]chan Event
+	nextSubID     int
+	mu            sync.RWMutex
 }
 
-func NewBattle(id string, p1 *models.Player, p2 *models.Player) (*Battle, error) {
+// NewBattle - Tạo battle giữa hai player theo team đã chọn, mọi lượt ngẫu nhiên lấy từ seed
+func NewBattle(id string, p1 *models.Player, p2 *models.Player, seed int64, format *BattleFormat) (*Battle, error) {
 	// Validate players
 	if p1.IsInBattle() || p2.IsInBattle() {
 		return nil, fmt.Errorf("player already in battle")
 	}
 
 	// Setup battle players
-	bp1, err := setupBattlePlayer(p1)
+	bp1, err := setupBattlePlayer(p1, format)
 	if err != nil {
 		return nil, err
 	}
-	bp2, err := setupBattlePlayer(p2)
+	bp2, err := setupBattlePlayer(p2, format)
 	if err != nil {
 		return nil, err
 	}
 
-	battle := &Battle{
-		ID:           id,
-		Player1:      bp1,
-		Player2:      bp2,
-		State:        BattleStateWaiting,
-		LastMoveTime: time.Now(),
-		StartTime:    time.Now(),
-		Logs:         make([]string, 0),
-	}
+	return newBattle(id, bp1, bp2, seed, format), nil
+}
 
-	return battle, nil
+// NewBattleWithTeams - Tạo battle trực tiếp từ team (NPC, replay, mô phỏng)
+func NewBattleWithTeams(id string, p1ID string, team1 []*models.Pokemon,
+	p2ID string, team2 []*models.Pokemon, seed int64) (*Battle, error) {
+	return newBattleWithTeams(id, p1ID, team1, p2ID, team2, seed, FormatStandard)
 }
 
-func setupBattlePlayer(p *models.Player) (*BattlePlayer, error) {
-	team := p.GetBattleTeam()
-	if len(team) != constants.MaxBattlePokemon {
-		return nil, fmt.Errorf("invalid battle team size: expected %d, got %d",
-			constants.MaxBattlePokemon, len(team))
+func newBattleWithTeams(id string, p1ID string, team1 []*models.Pokemon,
+	p2ID string, team2 []*models.Pokemon, seed int64, format *BattleFormat) (*Battle, error) {
+	if p1ID == p2ID {
+		return nil, fmt.Errorf("players must be different")
+	}
+	if len(team1) == 0 || len(team2) == 0 {
+		return nil, fmt.Errorf(constants.ErrInvalidBattleTeam)
 	}
 
-	battleTeam := make([]*models.Pokemon, len(team))
-	for i, num := range team {
-		pokemon, err := p.GetPokemon(num)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get pokemon %s: %v", num, err)
-		}
-		if !pokemon.IsAlive() {
-			return nil, fmt.Errorf("pokemon %s is not available for battle", num)
+	bp1 := &BattlePlayer{ID: p1ID, Team: team1}
+	bp2 := &BattlePlayer{ID: p2ID, Team: team2}
+	for _, bp := range []*BattlePlayer{bp1, bp2} {
+		for _, pokemon := range bp.Team {
+			if pokemon == nil || !pokemon.IsAlive() {
+				return nil, fmt.Errorf("pokemon is not available for battle")
+			}
 		}
-		battleTeam[i] = pokemon
+	}
+
+	return newBattle(id, bp1, bp2, seed, format), nil
+}
+
+func newBattle(id string, bp1, bp2 *BattlePlayer, seed int64, format *BattleFormat) *Battle {
+	battle := &Battle{
+		ID:            id,
+		Player1:       bp1,
+		Player2:       bp2,
+		State:         BattleStateWaiting,
+		Format:        format.Name,
+		ActiveSlots:   format.activeSlots(),
+		TurnModel:     format.turnModel(),
+		statusEnabled: format.StatusConditions,
+		Seed:          seed,
+		Events:        make([]Event, 0),
+		rng:           random.New(seed),
+		submitted:     make(map[string]Action),
+		timer:         newTurnTimer(format),
+		subscribers:   make(map[int]chan Event),
+		spectators:    make(map[int]chan Event),
+	}
+	battle.useClock(clock.Real())
+	// Luật sát thương đã được kiểm tra khi đăng ký thể thức
+	battle.modifiers, _ = getDamageModifiers(format.DamageRules)
+	for _, bp := range []*BattlePlayer{bp1, bp2} {
+		bp.initActive(battle.ActiveSlots)
+		bp.initStatus()
+	}
+	battle.recorder.snapshotTeams(bp1, bp2)
+	return battle
+}
+
+func setupBattlePlayer(p *models.Player, format *BattleFormat) (*BattlePlayer, error) {
+	team, err := playerTeam(p)
+	if err != nil {
+		return nil, err
+	}
+	if err := format.ValidateTeam(team); err != nil {
+		return nil, err
 	}
 
 	return &BattlePlayer{
 		ID:           p.GetID(), // Truy cập trực tiếp ID từ PlayerData
 		CurrentIndex: 0,
-		Team:         battleTeam,
+		Team:         format.prepareTeam(team),
 		IsReady:      false,
 		HasSurrender: false,
 	}, nil
 }
 
-// ExecuteMove - Thực hiện lượt đánh
+// ExecuteMove - Thực hiện lượt đánh vào mục tiêu mặc định
 func (b *Battle) ExecuteMove(playerID string, moveType string) error {
+	return b.ExecuteMoveAt(playerID, moveType, 0)
+}
+
+// ExecuteMoveAt - Thực hiện lượt đánh vào vị trí target (1, 2) của đối thủ, 0 là mục tiêu mặc định
+func (b *Battle) ExecuteMoveAt(playerID string, moveType string, target int) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
 	if b.State != BattleStateActive {
 		return fmt.Errorf("battle not active")
 	}
-	if b.CurrentTurn != playerID {
+	if !b.canAct(playerID) {
 		return fmt.Errorf("not your turn")
 	}
-	if time.Since(b.StartTime).Seconds() > float64(constants.BattleTimeout) {
-		b.State = BattleStateFinished
+	if b.clock.Now().Sub(b.StartTime).Seconds() > float64(constants.BattleTimeout) {
+		b.expire()
 		return fmt.Errorf("battle timeout")
 	}
 
-	attacker, defender := b.getCurrentPokemon(playerID)
-	if !attacker.IsAlive() || !defender.IsAlive() {
+	if err := b.executeMove(playerID, moveType, target); err != nil {
+		return err
+	}
+	b.recorder.recordMove(b.Turn, playerID, moveType, target)
+	b.timer.consecutive[playerID] = 0
+	return nil
+}
+
+// executeMove - Thực hiện lượt đánh của vị trí đang hành động khi đã giữ lock
+func (b *Battle) executeMove(playerID string, moveType string, target int) error {
+	player := b.getAttackingPlayer(playerID)
+	if player.PendingSwitch {
+		return fmt.Errorf("must switch pokemon first")
+	}
+	if target < 0 || target > len(b.getDefendingPlayer(playerID).Active) {
+		return fmt.Errorf("invalid target: %d", target)
+	}
+
+	attacker := player.activePokemon(b.CurrentSlot)
+	targetSlot, defender := b.resolveTarget(playerID, target)
+	if attacker == nil || defender == nil || !attacker.IsAlive() || !defender.IsAlive() {
 		return fmt.Errorf("invalid pokemon state")
 	}
 
-	// Validate move type
-	if moveType != constants.NormalAttackType && moveType != constants.SpecialAttackType {
-		return fmt.Errorf("invalid move type")
+	if err := validateMove(attacker, moveType); err != nil {
+		return err
+	}
+
+	if b.TurnModel == TurnModelSimultaneous {
+		return b.submit(playerID, Action{Kind: ActionAttack, Move: moveType, Target: target})
 	}
+	if b.statusBlocksAction(player, b.CurrentSlot) {
+		b.switchTurn()
+		b.LastMoveTime = b.clock.Now()
+		return nil
+	}
+	if err := b.attack(playerID, b.CurrentSlot, attacker, targetSlot, defender, moveType); err != nil {
+		return err
+	}
+
+	b.switchTurn()
+	b.LastMoveTime = b.clock.Now()
+	return nil
+}
+
+// attack - Pokemon ở vị trí slot tấn công Pokemon ở vị trí targetSlot của đối thủ
+func (b *Battle) attack(playerID string, slot int, attacker *models.Pokemon, targetSlot int,
+	defender *models.Pokemon, moveType string) error {
+	usePP(attacker, moveType)
+	b.emit(Event{
+		Type:           EventActionChosen,
+		PlayerID:       playerID,
+		Pokemon:        attacker.Name,
+		Position:       b.position(slot),
+		TargetPosition: b.position(targetSlot),
+		Move:           moveType,
+	})
 
 	// Calculate and apply damage
-	damage := b.calculateDamage(attacker, defender, moveType)
-	defender.CurrentStats.HP -= damage
-	b.logMove(playerID, attacker, defender, moveType, damage)
+	breakdown := b.calculateDamage(attacker, defender, moveType)
+	defender.CurrentStats.HP -= breakdown.Damage
+	b.logMove(playerID, slot, attacker, targetSlot, defender, moveType, breakdown)
 
 	// Check if defender fainted
 	if !defender.IsAlive() {
-		if err := b.handleFaintedPokemon(playerID); err != nil {
-			return err
-		}
+		b.emit(Event{
+			Type:     EventFaint,
+			PlayerID: b.getDefendingPlayer(playerID).ID,
+			Pokemon:  defender.Name,
+			Position: b.position(targetSlot),
+		})
+		return b.handleFaintedPokemon(playerID, targetSlot)
 	}
-
-	b.switchTurn()
-	b.LastMoveTime = time.Now()
+	b.tryInflictStatus(playerID, targetSlot, attacker, breakdown)
 	return nil
 }
 
@@ -143,6 +274,10 @@ func (b *Battle) StartBattle() error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
+	return b.startBattle()
+}
+
+func (b *Battle) startBattle() error {
 	if b.State != BattleStateWaiting {
 		return fmt.Errorf("battle in invalid state")
 	}
@@ -154,22 +289,30 @@ func (b *Battle) StartBattle() error {
 	p1Speed := b.Player1.Team[0].CurrentStats.Speed
 	p2Speed := b.Player2.Team[0].CurrentStats.Speed
 
-	if p1Speed > p2Speed {
+	switch {
+	case b.TurnModel == TurnModelSimultaneous:
+		// Cả hai player chọn hành động cùng lúc, không có người đi trước
+		b.CurrentTurn = ""
+	case b.ActiveSlots > 1:
+		// Battle đôi - cả bốn Pokemon hành động theo thứ tự tốc độ
+		b.turnOrder = b.speedOrder()
+		b.nextActor()
+	case p1Speed > p2Speed:
 		b.CurrentTurn = b.Player1.ID
-	} else if p2Speed > p1Speed {
+	case p2Speed > p1Speed:
 		b.CurrentTurn = b.Player2.ID
-	} else {
+	case b.rng.Float64() < 0.5:
 		// Random if speed equal
-		if rand.Float64() < 0.5 {
-			b.CurrentTurn = b.Player1.ID
-		} else {
-			b.CurrentTurn = b.Player2.ID
-		}
+		b.CurrentTurn = b.Player1.ID
+	default:
+		b.CurrentTurn = b.Player2.ID
 	}
 
 	b.State = BattleStateActive
-	b.StartTime = time.Now()
-	b.LastMoveTime = time.Now()
+	b.StartTime = b.clock.Now()
+	b.LastMoveTime = b.StartTime
+	b.emit(Event{Type: EventBattleStart, PlayerID: b.CurrentTurn})
+	b.beginTurn()
 	return nil
 }
 
@@ -192,49 +335,147 @@ func (b *Battle) SetPlayerReady(playerID string) error {
 
 	// Auto start if both ready
 	if b.Player1.IsReady && b.Player2.IsReady {
-		return b.StartBattle()
+		return b.startBattle()
 	}
 
 	return nil
 }
 
-func (b *Battle) calculateDamage(attacker, defender *models.Pokemon, moveType string) int {
-	if moveType == constants.NormalAttackType {
-		return attacker.CurrentStats.Attack - defender.CurrentStats.Defense
+// computeDamage - Tính sát thương theo công thức gốc, không phụ thuộc trạng thái battle
+func computeDamage(attacker, defender *models.Pokemon, moveType string) DamageBreakdown {
+	if move := attacker.FindMove(moveType); move != nil {
+		return computeMoveDamage(attacker, defender, move)
 	}
+	breakdown := DamageBreakdown{MoveType: moveType, TypeMultiplier: 1.0}
 
-	// Special attack with type effectiveness
-	baseDamage := attacker.CurrentStats.SpecialAtk
-	maxMultiplier := 1.0
-
-	for _, attackType := range attacker.GetTypes() {
-		if effectiveness, exists := constants.TypeEffectiveness[attackType]; exists {
-			for _, defenderType := range defender.GetTypes() {
-				if multiplier, exists := effectiveness[defenderType]; exists {
-					if multiplier > maxMultiplier {
-						maxMultiplier = multiplier
+	if moveType == constants.NormalAttackType {
+		breakdown.AttackStat = attacker.CurrentStats.Attack
+		breakdown.DefenseStat = defender.CurrentStats.Defense
+		breakdown.RawDamage = breakdown.AttackStat - breakdown.DefenseStat
+	} else {
+		// Special attack with type effectiveness
+		breakdown.AttackStat = attacker.CurrentStats.SpecialAtk
+		breakdown.DefenseStat = defender.CurrentStats.SpecialDef
+
+		for _, attackType := range attacker.GetTypes() {
+			if effectiveness, exists := constants.TypeEffectiveness[attackType]; exists {
+				for _, defenderType := range defender.GetTypes() {
+					if multiplier, exists := effectiveness[defenderType]; exists {
+						if multiplier > breakdown.TypeMultiplier {
+							breakdown.TypeMultiplier = multiplier
+							breakdown.AttackType = attackType
+						}
 					}
 				}
 			}
 		}
+
+		breakdown.RawDamage = int(float64(breakdown.AttackStat)*breakdown.TypeMultiplier) - breakdown.DefenseStat
 	}
 
-	return int(float64(baseDamage)*maxMultiplier) - defender.CurrentStats.SpecialDef
+	breakdown.Damage = breakdown.RawDamage
+	return breakdown
 }
 
-func (b *Battle) handleFaintedPokemon(playerID string) error {
+// handleFaintedPokemon - Thay Pokemon vừa bị hạ ở vị trí slot của đối thủ
+func (b *Battle) handleFaintedPokemon(playerID string, slot int) error {
 	defender := b.getDefendingPlayer(playerID)
 
-	// Find next available Pokemon
-	for i := defender.CurrentIndex + 1; i < len(defender.Team); i++ {
-		if defender.Team[i].IsAlive() {
-			defender.CurrentIndex = i
-			return nil
-		}
+	// Find available Pokemon
+	options := defender.aliveOptions()
+	switch {
+	case len(options) == 0 && !defender.hasActiveAlive():
+		// No more Pokemon available
+		return b.endBattle(b.getAttackingPlayer(playerID).ID, EndReasonKO)
+	case len(options) == 0:
+		// Hết Pokemon dự bị - vị trí bỏ trống, Pokemon còn lại tiếp tục chiến đấu
+		defender.setActive(slot, -1)
+	case len(options) == 1:
+		// Chỉ còn một lựa chọn - tự động đổi
+		defender.setActive(slot, options[0])
+		b.emit(Event{
+			Type:     EventSwitch,
+			PlayerID: defender.ID,
+			Pokemon:  defender.Team[options[0]].Name,
+			Slot:     options[0],
+			Position: b.position(slot),
+		})
+	default:
+		// Player phải tự chọn Pokemon thay thế ở đầu lượt kế tiếp
+		defender.PendingSwitch = true
+		defender.PendingSlots = append(defender.PendingSlots, slot)
+		b.emit(Event{Type: EventSwitchRequest, PlayerID: defender.ID, Options: options, Position: b.position(slot)})
+	}
+	return nil
+}
+
+// SwitchPokemon - Đổi Pokemon đang ra trận; đổi tự nguyện sẽ kết thúc lượt
+func (b *Battle) SwitchPokemon(playerID string, index int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.State != BattleStateActive {
+		return fmt.Errorf("battle not active")
+	}
+	if !b.canAct(playerID) {
+		return fmt.Errorf("not your turn")
 	}
 
-	// No more Pokemon available
-	return b.endBattle(b.getAttackingPlayer(playerID).ID)
+	if err := b.switchPokemon(playerID, index); err != nil {
+		return err
+	}
+	b.recorder.recordSwitch(b.Turn, playerID, index)
+	b.timer.consecutive[playerID] = 0
+	return nil
+}
+
+// switchPokemon - Đổi Pokemon khi đã giữ lock
+func (b *Battle) switchPokemon(playerID string, index int) error {
+	player := b.getAttackingPlayer(playerID)
+	if index < 0 || index >= len(player.Team) {
+		return fmt.Errorf("invalid pokemon index: %d", index)
+	}
+	if player.isActive(index) && (!player.PendingSwitch || player.Team[index].IsAlive()) {
+		return fmt.Errorf("pokemon is already in battle")
+	}
+	if !player.Team[index].IsAlive() {
+		return fmt.Errorf("pokemon %s is not able to battle", player.Team[index].Name)
+	}
+
+	if b.TurnModel == TurnModelSimultaneous && !player.PendingSwitch {
+		return b.submit(playerID, Action{Kind: ActionSwitch, SwitchTo: index})
+	}
+
+	forced := player.PendingSwitch
+	slot := b.CurrentSlot
+	if forced {
+		slot = player.PendingSlots[0]
+		player.PendingSlots = player.PendingSlots[1:]
+		player.PendingSwitch = len(player.PendingSlots) > 0
+	}
+	player.setActive(slot, index)
+	b.emit(Event{
+		Type:     EventSwitch,
+		PlayerID: playerID,
+		Pokemon:  player.Team[index].Name,
+		Slot:     index,
+		Position: b.position(slot),
+	})
+
+	if forced && b.TurnModel == TurnModelSimultaneous {
+		// Lượt mới bắt đầu khi mọi Pokemon bị hạ đã được thay
+		if !b.Player1.PendingSwitch && !b.Player2.PendingSwitch {
+			b.beginTurn()
+		}
+		return nil
+	}
+	if forced && b.ActiveSlots == 1 {
+		// Đổi bắt buộc không tốn lượt - player vẫn được đánh
+		b.startTurnTimer()
+		return nil
+	}
+	b.switchTurn()
+	return nil
 }
 
 func (b *Battle) Surrender(playerID string) error {
@@ -244,18 +485,26 @@ func (b *Battle) Surrender(playerID string) error {
 	if b.State != BattleStateActive {
 		return fmt.Errorf("battle not active")
 	}
-
-	if playerID == b.Player1.ID {
-		b.Player1.HasSurrender = true
-		return b.endBattle(b.Player2.ID)
-	} else {
-		b.Player2.HasSurrender = true
-		return b.endBattle(b.Player1.ID)
+	if !b.HasPlayer(playerID) {
+		return fmt.Errorf("invalid player ID")
 	}
+
+	b.recorder.record(b.Turn, playerID, ReplayActionSurrender, "")
+	return b.surrender(playerID)
+}
+
+func (b *Battle) surrender(playerID string) error {
+	b.getAttackingPlayer(playerID).HasSurrender = true
+	b.emit(Event{Type: EventSurrender, PlayerID: playerID})
+	return b.endBattle(b.getDefendingPlayer(playerID).ID, EndReasonSurrender)
 }
 
-func (b *Battle) endBattle(winnerID string) error {
+func (b *Battle) endBattle(winnerID string, reason EndReason) error {
 	b.State = BattleStateFinished
+	b.WinnerID = winnerID
+	b.EndReason = reason
+	b.EndTime = b.clock.Now()
+	b.stopTurnTimer()
 
 	// Calculate total exp from losing team
 	var totalExp int
@@ -278,10 +527,63 @@ func (b *Battle) endBattle(winnerID string) error {
 	for _, pokemon := range winnerTeam {
 		if pokemon.IsAlive() {
 			pokemon.AddExperience(expPerPokemon)
+			b.emit(Event{Type: EventReward, PlayerID: winnerID, Pokemon: pokemon.Name, Exp: expPerPokemon})
 		}
 	}
 
+	b.emit(Event{Type: EventBattleEnd, WinnerID: winnerID, Reason: reason})
+	b.closeSubscribers()
 	return nil
 }
 
+// expire - Kết thúc battle do hết thời gian, không có người thắng
+func (b *Battle) expire() {
+	if b.State == BattleStateActive {
+		b.recorder.record(b.Turn, "", ReplayActionExpire, "")
+	}
+	b.State = BattleStateFinished
+	b.EndReason = EndReasonExpired
+	b.EndTime = b.clock.Now()
+	b.stopTurnTimer()
+	b.emit(Event{Type: EventBattleEnd, Reason: EndReasonExpired})
+	b.closeSubscribers()
+}
+
+// GetState - Lấy trạng thái hiện tại của battle
+func (b *Battle) GetState() BattleState {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.State
+}
+
+// GetWinner - Lấy ID người thắng (rỗng nếu chưa có)
+func (b *Battle) GetWinner() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.WinnerID
+}
+
+// GetResult - Lấy người thắng và lý do kết thúc (rỗng nếu battle chưa kết thúc)
+func (b *Battle) GetResult() (string, EndReason) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.WinnerID, b.EndReason
+}
+
+// IsPlayerReady - Kiểm tra player đã sẵn sàng chưa
+func (b *Battle) IsPlayerReady(playerID string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.Player1.ID == playerID {
+		return b.Player1.IsReady
+	}
+	return b.Player2.ID == playerID && b.Player2.IsReady
+}
+
+// HasPlayer - Kiểm tra player có tham gia battle không
+func (b *Battle) HasPlayer(playerID string) bool {
+	return b.Player1.ID == playerID || b.Player2.ID == playerID
+}
+
 // Helper methods...
@@ -1,22 +1,41 @@
 package pokebat
 
 import (
-	"fmt"
-	"time"
+	"sort"
 
 	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
 )
 
-func (b *Battle) getCurrentPokemon(playerID string) (*models.Pokemon, *models.Pokemon) {
-	var attacker, defender *models.Pokemon
-	if playerID == b.Player1.ID {
-		attacker = b.Player1.Team[b.Player1.CurrentIndex]
-		defender = b.Player2.Team[b.Player2.CurrentIndex]
-	} else {
-		attacker = b.Player2.Team[b.Player2.CurrentIndex]
-		defender = b.Player1.Team[b.Player1.CurrentIndex]
+// actor - Một vị trí ra trận trong thứ tự hành động của vòng (battle đôi)
+type actor struct {
+	playerID string
+	slot     int
+	speed    int
+	tiebreak float64
+}
+
+// resolveTarget - Vị trí của đối thủ bị nhắm tới, vị trí trống hoặc đã bị hạ thì chuyển sang vị trí còn lại
+func (b *Battle) resolveTarget(playerID string, target int) (int, *models.Pokemon) {
+	defender := b.getDefendingPlayer(playerID)
+	slot := target - 1
+	if slot < 0 {
+		slot = 0
+	}
+	for i := range defender.Active {
+		candidate := (slot + i) % len(defender.Active)
+		if pokemon := defender.activePokemon(candidate); pokemon != nil && pokemon.IsAlive() {
+			return candidate, pokemon
+		}
 	}
-	return attacker, defender
+	return slot, defender.activePokemon(slot)
+}
+
+// position - Vị trí ra trận ghi trong event: 1, 2 ở battle đôi, 0 ở battle đơn
+func (b *Battle) position(slot int) int {
+	if b.ActiveSlots < 2 {
+		return 0
+	}
+	return slot + 1
 }
 
 func (b *Battle) getAttackingPlayer(playerID string) *BattlePlayer {
@@ -33,22 +52,161 @@ func (b *Battle) getDefendingPlayer(playerID string) *BattlePlayer {
 	return b.Player1
 }
 
+// initActive - Đưa các Pokemon đầu team ra trận
+func (bp *BattlePlayer) initActive(slots int) {
+	if slots > len(bp.Team) {
+		slots = len(bp.Team)
+	}
+	bp.Active = make([]int, slots)
+	for i := range bp.Active {
+		bp.Active[i] = i
+	}
+	bp.CurrentIndex = 0
+}
+
+// activePokemon - Pokemon ở vị trí ra trận (nil nếu vị trí bỏ trống)
+func (bp *BattlePlayer) activePokemon(slot int) *models.Pokemon {
+	if slot < 0 || slot >= len(bp.Active) || bp.Active[slot] < 0 {
+		return nil
+	}
+	return bp.Team[bp.Active[slot]]
+}
+
+// setActive - Đưa Pokemon thứ index vào vị trí ra trận, -1 để bỏ trống
+func (bp *BattlePlayer) setActive(slot, index int) {
+	bp.Active[slot] = index
+	if slot == 0 && index >= 0 {
+		bp.CurrentIndex = index
+	}
+}
+
+// isActive - Pokemon thứ index đang ở một vị trí ra trận
+func (bp *BattlePlayer) isActive(index int) bool {
+	for _, active := range bp.Active {
+		if active == index {
+			return true
+		}
+	}
+	return false
+}
+
+// hasActiveAlive - Còn Pokemon đang ra trận chiến đấu được
+func (bp *BattlePlayer) hasActiveAlive() bool {
+	for slot := range bp.Active {
+		if pokemon := bp.activePokemon(slot); pokemon != nil && pokemon.IsAlive() {
+			return true
+		}
+	}
+	return false
+}
+
+// aliveOptions - Danh sách vị trí Pokemon còn chiến đấu được (trừ Pokemon đang ra trận)
+func (bp *BattlePlayer) aliveOptions() []int {
+	options := make([]int, 0, len(bp.Team))
+	for i, pokemon := range bp.Team {
+		if !bp.isActive(i) && pokemon.IsAlive() {
+			options = append(options, i)
+		}
+	}
+	return options
+}
+
 func (b *Battle) switchTurn() {
-	if b.CurrentTurn == b.Player1.ID {
+	if b.ActiveSlots > 1 {
+		b.nextActor()
+	} else if b.CurrentTurn == b.Player1.ID {
 		b.CurrentTurn = b.Player2.ID
 	} else {
 		b.CurrentTurn = b.Player1.ID
 	}
-	b.LastMoveTime = time.Now()
+	b.LastMoveTime = b.clock.Now()
+	b.beginTurn()
+}
+
+// beginTurn - Bắt đầu lượt mới: tăng số lượt, phát event và chạy đồng hồ
+func (b *Battle) beginTurn() {
+	if b.State != BattleStateActive {
+		return
+	}
+	b.Turn++
+	b.emit(Event{Type: EventTurnStart, PlayerID: b.CurrentTurn, Position: b.position(b.CurrentSlot)})
+
+	// Sát thương trạng thái đầu lượt của Pokemon sắp hành động (lượt đồng thời tính ở cuối lượt)
+	if b.statusEnabled && b.TurnModel != TurnModelSimultaneous {
+		player := b.getAttackingPlayer(b.CurrentTurn)
+		b.applyStatusDamage(player, b.CurrentSlot)
+		if b.State != BattleStateActive {
+			return
+		}
+		if !player.PendingSwitch && player.activePokemon(b.CurrentSlot) == nil {
+			// Pokemon bị hạ để lại vị trí trống - chuyển sang vị trí kế tiếp
+			b.switchTurn()
+			return
+		}
+	}
+	b.startTurnTimer()
+}
+
+// nextActor - Chọn vị trí hành động tiếp theo ở battle đôi: player đang chờ đổi Pokemon được ưu tiên,
+// sau đó lần lượt theo tốc độ, hết vòng thì xếp lại thứ tự
+func (b *Battle) nextActor() {
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		if player.PendingSwitch {
+			b.CurrentTurn, b.CurrentSlot = player.ID, player.PendingSlots[0]
+			return
+		}
+	}
+
+	for pass := 0; pass < 2; pass++ {
+		for len(b.turnOrder) > 0 {
+			next := b.turnOrder[0]
+			b.turnOrder = b.turnOrder[1:]
+			// Pokemon bị hạ trước khi tới lượt thì bỏ qua
+			if pokemon := b.getAttackingPlayer(next.playerID).activePokemon(next.slot); pokemon != nil && pokemon.IsAlive() {
+				b.CurrentTurn, b.CurrentSlot = next.playerID, next.slot
+				return
+			}
+		}
+		b.turnOrder = b.speedOrder()
+	}
+}
+
+// speedOrder - Thứ tự hành động của các Pokemon đang ra trận theo tốc độ, bằng tốc độ thì ngẫu nhiên
+func (b *Battle) speedOrder() []actor {
+	order := make([]actor, 0, 2*b.ActiveSlots)
+	for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+		for slot := range player.Active {
+			if pokemon := player.activePokemon(slot); pokemon != nil && pokemon.IsAlive() {
+				order = append(order, actor{
+					playerID: player.ID,
+					slot:     slot,
+					speed:    player.speedOf(player.Active[slot]),
+					tiebreak: b.rng.Float64(),
+				})
+			}
+		}
+	}
+	sort.Slice(order, func(i, j int) bool {
+		if order[i].speed != order[j].speed {
+			return order[i].speed > order[j].speed
+		}
+		return order[i].tiebreak < order[j].tiebreak
+	})
+	return order
 }
 
-func (b *Battle) logMove(playerID string, attacker, defender *models.Pokemon, moveType string, damage int) {
-	log := fmt.Sprintf("%s's %s used %s attack on %s's %s for %d damage",
-		playerID,
-		attacker.Name,
-		moveType,
-		b.getDefendingPlayer(playerID).ID,
-		defender.Name,
-		damage)
-	b.Logs = append(b.Logs, log)
+func (b *Battle) logMove(playerID string, slot int, attacker *models.Pokemon, targetSlot int, defender *models.Pokemon,
+	moveType string, breakdown DamageBreakdown) {
+	b.emit(Event{
+		Type:           EventDamage,
+		PlayerID:       playerID,
+		Pokemon:        attacker.Name,
+		Position:       b.position(slot),
+		TargetPlayerID: b.getDefendingPlayer(playerID).ID,
+		Target:         defender.Name,
+		TargetPosition: b.position(targetSlot),
+		Move:           moveType,
+		Damage:         &breakdown,
+		RemainingHP:    defender.CurrentStats.HP,
+	})
 }
@@ -0,0 +1,159 @@
+package pokebat
+
+import (
+	"testing"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/clock"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/config"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/random"
+)
+
+// newTestPlayer - Player đã chọn team gồm các loài theo tên ở level 10
+func newTestPlayer(t *testing.T, clk clock.Clock, id string, names ...string) *models.Player {
+	t.Helper()
+	player := models.NewPlayerWithSources(id, config.Default(), clk, random.New(1))
+	t.Cleanup(func() { player.Cleanup() })
+
+	team := make([]string, 0, len(names))
+	for _, pokemon := range newTestTeam(t, 10, names...) {
+		if err := player.AddPokemon(pokemon); err != nil {
+			t.Fatal(err)
+		}
+		team = append(team, pokemon.Number)
+	}
+	if err := player.SelectBattleTeam(team); err != nil {
+		t.Fatal(err)
+	}
+	return player
+}
+
+func TestManagerSeedsBattleFromClock(t *testing.T) {
+	clk := clock.NewFake(testStart)
+	manager := NewManagerWithClock(clk)
+	t.Cleanup(manager.Cleanup)
+	challenger := newTestPlayer(t, clk, "seed-challenger", "Bulbasaur", "Charmander", "Squirtle")
+	opponent := newTestPlayer(t, clk, "seed-opponent", "Pidgey", "Rattata", "Pikachu")
+
+	challenge, err := manager.Challenge(challenger, opponent, FormatStandard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	battle, err := manager.AcceptChallenge(challenge.ID, challenger, opponent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := testStart.UnixNano(); battle.Seed != want {
+		t.Errorf("battle seed = %d, want %d from the manager clock", battle.Seed, want)
+	}
+}
+
+// TestSpeedTieFollowsSeed - Hai Pokemon cùng tốc độ: người đi trước chỉ phụ thuộc seed
+// và cả hai bên đều có lúc được đi trước
+func TestSpeedTieFollowsSeed(t *testing.T) {
+	firstTurn := func(seed int64) string {
+		battle, err := NewBattleWithTeams("speed-tie", "p1", newTestTeam(t, 20, "Pikachu"),
+			"p2", newTestTeam(t, 20, "Pikachu"), seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return startTestBattle(t, battle)
+	}
+
+	first := make(map[string]int)
+	for seed := int64(1); seed <= 20; seed++ {
+		turn := firstTurn(seed)
+		if again := firstTurn(seed); again != turn {
+			t.Fatalf("seed %d: first turn %s, then %s", seed, turn, again)
+		}
+		first[turn]++
+	}
+	if first["p1"] == 0 || first["p2"] == 0 {
+		t.Errorf("speed tie always went the same way over 20 seeds: %v", first)
+	}
+}
+
+// rollModifier - Áp dụng modifier n lần với RNG theo seed, trả về breakdown của từng lần
+func rollModifier(modifier DamageModifierFunc, seed int64, n int) []DamageBreakdown {
+	rng := random.New(seed)
+	rolls := make([]DamageBreakdown, n)
+	for i := range rolls {
+		rolls[i].Modifier = 1.0
+		modifier.Apply(&DamageContext{Breakdown: &rolls[i], Rng: rng})
+	}
+	return rolls
+}
+
+func TestDamageVarianceRange(t *testing.T) {
+	rolls := rollModifier(applyVariance, 5, 1000)
+	low, high := 1.0, 0.0
+	for _, roll := range rolls {
+		if roll.Modifier < constants.MinDamageVariance || roll.Modifier >= 1 {
+			t.Fatalf("variance modifier %v outside [%v, 1)", roll.Modifier, constants.MinDamageVariance)
+		}
+		low = min(low, roll.Modifier)
+		high = max(high, roll.Modifier)
+	}
+	// 1000 lần lăn phủ gần hết khoảng dao động
+	if low > constants.MinDamageVariance+0.01 || high < 0.99 {
+		t.Errorf("variance over 1000 rolls only spans [%v, %v]", low, high)
+	}
+
+	for i, roll := range rollModifier(applyVariance, 5, len(rolls)) {
+		if roll != rolls[i] {
+			t.Fatalf("roll %d differs for the same seed: %+v, then %+v", i, rolls[i], roll)
+		}
+	}
+}
+
+func TestCriticalRolls(t *testing.T) {
+	const n = 2000
+	rolls := rollModifier(applyCritical, 9, n)
+	crits := 0
+	for _, roll := range rolls {
+		switch {
+		case roll.Critical && roll.Modifier != constants.CriticalMultiplier:
+			t.Fatalf("critical hit with modifier %v, want %v", roll.Modifier, constants.CriticalMultiplier)
+		case !roll.Critical && roll.Modifier != 1:
+			t.Fatalf("normal hit with modifier %v, want 1", roll.Modifier)
+		case roll.Critical:
+			crits++
+		}
+	}
+	// Tỉ lệ chí mạng lệch khỏi CriticalChance không quá 3 điểm phần trăm
+	if rate := float64(crits) / n; rate < constants.CriticalChance-0.03 || rate > constants.CriticalChance+0.03 {
+		t.Errorf("critical rate %.3f over %d rolls, want about %v", rate, n, constants.CriticalChance)
+	}
+
+	for i, roll := range rollModifier(applyCritical, 9, n) {
+		if roll != rolls[i] {
+			t.Fatalf("roll %d differs for the same seed: %+v, then %+v", i, rolls[i], roll)
+		}
+	}
+}
+
+// TestSeededBattleDamageIsDeterministic - Cùng seed thì cùng chuỗi sát thương, kể cả chí mạng và dao động
+func TestSeededBattleDamageIsDeterministic(t *testing.T) {
+	play := func(seed int64) []Event {
+		battle, err := newBattleWithTeams("seeded-damage", "p1", newTestTeam(t, 30, "Charmander"),
+			"p2", newTestTeam(t, 30, "Bulbasaur"), seed, FormatCompetitive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := PlayAgents(battle, NewRandomAgent(1), NewRandomAgent(2), 1000); err != nil {
+			t.Fatal(err)
+		}
+		return eventsOfType(battle.Events, EventDamage)
+	}
+
+	first, again := play(11), play(11)
+	if len(first) == 0 || len(first) != len(again) {
+		t.Fatalf("damage events: %d, then %d", len(first), len(again))
+	}
+	for i := range first {
+		if *first[i].Damage != *again[i].Damage {
+			t.Fatalf("damage %d differs for the same seed: %+v, then %+v", i, *first[i].Damage, *again[i].Damage)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package pokebat
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/random"
+)
+
+// Tên các luật sát thương có sẵn, bật theo thể thức
+const (
+	DamageRuleAccuracy = "accuracy"        // Đòn đánh có thể trượt
+	DamageRuleCritical = "critical"        // Đòn đánh có thể chí mạng
+	DamageRuleSameType = "same_type_bonus" // Thưởng sát thương khi type của đòn trùng type của Pokemon
+	DamageRuleVariance = "variance"        // Sát thương dao động ngẫu nhiên
+)
+
+// DamageContext - Dữ liệu một bước trong chuỗi tính sát thương được đọc và sửa
+type DamageContext struct {
+	Attacker  *models.Pokemon
+	Defender  *models.Pokemon
+	Breakdown *DamageBreakdown
+	Rng       random.Source // RNG có seed của battle để replay tái hiện chính xác
+}
+
+// DamageModifier - Một bước trong chuỗi tính sát thương sau công thức gốc
+type DamageModifier interface {
+	Apply(ctx *DamageContext)
+}
+
+// DamageModifierFunc - Dùng hàm như một DamageModifier
+type DamageModifierFunc func(ctx *DamageContext)
+
+func (f DamageModifierFunc) Apply(ctx *DamageContext) {
+	f(ctx)
+}
+
+var (
+	damageModifiers = map[string]DamageModifier{
+		DamageRuleAccuracy: DamageModifierFunc(applyAccuracy),
+		DamageRuleCritical: DamageModifierFunc(applyCritical),
+		DamageRuleSameType: DamageModifierFunc(applySameType),
+		DamageRuleVariance: DamageModifierFunc(applyVariance),
+	}
+	damageModifiersMu sync.RWMutex
+)
+
+// RegisterDamageModifier - Thêm luật sát thương mới để thể thức có thể bật theo tên
+func RegisterDamageModifier(name string, modifier DamageModifier) error {
+	damageModifiersMu.Lock()
+	defer damageModifiersMu.Unlock()
+
+	if _, exists := damageModifiers[name]; exists {
+		return fmt.Errorf("damage rule %s already registered", name)
+	}
+	damageModifiers[name] = modifier
+	return nil
+}
+
+// getDamageModifiers - Lấy chuỗi modifier theo thứ tự luật của thể thức
+func getDamageModifiers(rules []string) ([]DamageModifier, error) {
+	damageModifiersMu.RLock()
+	defer damageModifiersMu.RUnlock()
+
+	modifiers := make([]DamageModifier, 0, len(rules))
+	for _, rule := range rules {
+		modifier, exists := damageModifiers[rule]
+		if !exists {
+			return nil, fmt.Errorf("unknown damage rule: %s", rule)
+		}
+		modifiers = append(modifiers, modifier)
+	}
+	return modifiers, nil
+}
+
+// calculateDamage - Công thức gốc rồi lần lượt qua các luật sát thương của thể thức
+func (b *Battle) calculateDamage(attacker, defender *models.Pokemon, moveType string) DamageBreakdown {
+	breakdown := computeDamage(attacker, defender, moveType)
+	if len(b.modifiers) == 0 {
+		return breakdown
+	}
+
+	breakdown.Modifier = 1.0
+	ctx := &DamageContext{Attacker: attacker, Defender: defender, Breakdown: &breakdown, Rng: b.rng}
+	for _, modifier := range b.modifiers {
+		if breakdown.Missed {
+			break
+		}
+		modifier.Apply(ctx)
+	}
+
+	if breakdown.Missed || breakdown.TypeMultiplier == 0 {
+		breakdown.Damage = 0
+		return breakdown
+	}
+	breakdown.Damage = int(float64(breakdown.RawDamage) * breakdown.Modifier)
+	if breakdown.Damage < constants.MinDamage {
+		breakdown.Damage = constants.MinDamage
+	}
+	return breakdown
+}
+
+// applyAccuracy - Đòn đánh trượt thì không gây sát thương. Đòn đã học dùng độ chính xác riêng.
+func applyAccuracy(ctx *DamageContext) {
+	accuracy := constants.MoveAccuracy
+	if ctx.Breakdown.Accuracy > 0 {
+		accuracy = float64(ctx.Breakdown.Accuracy) / 100
+	}
+	if ctx.Rng.Float64() >= accuracy {
+		ctx.Breakdown.Missed = true
+	}
+}
+
+// applyCritical - Chí mạng nhân sát thương
+func applyCritical(ctx *DamageContext) {
+	if ctx.Rng.Float64() < constants.CriticalChance {
+		ctx.Breakdown.Critical = true
+		ctx.Breakdown.Modifier *= constants.CriticalMultiplier
+	}
+}
+
+// applySameType - Thưởng sát thương khi type của đòn trùng một type của Pokemon tấn công
+func applySameType(ctx *DamageContext) {
+	moveType := moveElement(ctx.Attacker, ctx.Breakdown)
+	for _, pokemonType := range ctx.Attacker.GetTypes() {
+		if pokemonType == moveType {
+			ctx.Breakdown.SameTypeBonus = true
+			ctx.Breakdown.Modifier *= constants.SameTypeMultiplier
+			return
+		}
+	}
+}
+
+// applyVariance - Sát thương dao động từ MinDamageVariance đến 100%
+func applyVariance(ctx *DamageContext) {
+	variance := constants.MinDamageVariance + ctx.Rng.Float64()*(1-constants.MinDamageVariance)
+	ctx.Breakdown.Modifier *= variance
+}
+
+// moveElement - Type của đòn: đòn đã học mang type riêng, đòn thường mang type Normal, đòn đặc biệt
+// mang type cho hệ số cao nhất hoặc type đầu tiên của Pokemon
+func moveElement(attacker *models.Pokemon, breakdown *DamageBreakdown) string {
+	if breakdown.Category != "" {
+		return breakdown.AttackType
+	}
+	if breakdown.MoveType == constants.NormalAttackType {
+		return constants.NormalMoveType
+	}
+	if breakdown.AttackType != "" {
+		return breakdown.AttackType
+	}
+	if types := attacker.GetTypes(); len(types) > 0 {
+		return types[0]
+	}
+	return ""
+}
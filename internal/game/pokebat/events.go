@@ -0,0 +1,237 @@
+package pokebat
+
+import (
+	"fmt"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventBattleStart     EventType = "battle_start"
+	EventTurnStart       EventType = "turn_start"
+	EventActionChosen    EventType = "action_chosen"
+	EventDamage          EventType = "damage"
+	EventFaint           EventType = "faint"
+	EventSwitch          EventType = "switch"
+	EventSwitchRequest   EventType = "switch_request"
+	EventStatusInflicted EventType = "status_inflicted"
+	EventStatusDamage    EventType = "status_damage"
+	EventStatusBlocked   EventType = "status_blocked"
+	EventStatusCured     EventType = "status_cured"
+	EventCaptured        EventType = "captured"
+	EventCaptureFailed   EventType = "capture_failed"
+	EventSurrender       EventType = "surrender"
+	EventTimeout         EventType = "timeout"
+	EventReward          EventType = "reward"
+	EventBattleEnd       EventType = "battle_end"
+)
+
+// DamageBreakdown - Chi tiết cách tính sát thương của một đòn đánh
+type DamageBreakdown struct {
+	MoveType       string  `json:"move_type"`
+	AttackStat     int     `json:"attack_stat"`
+	DefenseStat    int     `json:"defense_stat"`
+	AttackType     string  `json:"attack_type,omitempty"` // Type cho hệ số cao nhất, hoặc type của đòn đã học
+	Category       string  `json:"category,omitempty"`    // Loại đòn đã học (physical/special), trống với đòn generic
+	Power          int     `json:"power,omitempty"`
+	Accuracy       int     `json:"accuracy,omitempty"`
+	TypeMultiplier float64 `json:"type_multiplier"`
+	RawDamage      int     `json:"raw_damage"`
+	Modifier       float64 `json:"modifier,omitempty"` // Tích hệ số của các luật sát thương
+	Missed         bool    `json:"missed,omitempty"`
+	Critical       bool    `json:"critical,omitempty"`
+	SameTypeBonus  bool    `json:"same_type_bonus,omitempty"`
+	Damage         int     `json:"damage"`
+}
+
+// Event - Một sự kiện có cấu trúc trong battle
+type Event struct {
+	Seq            int              `json:"seq"`
+	BattleID       string           `json:"battle_id"`
+	Turn           int              `json:"turn"`
+	Type           EventType        `json:"type"`
+	Time           time.Time        `json:"time"`
+	PlayerID       string           `json:"player_id,omitempty"`
+	Pokemon        string           `json:"pokemon,omitempty"`
+	Slot           int              `json:"slot,omitempty"`
+	Position       int              `json:"position,omitempty"` // Vị trí ra trận (1, 2) ở battle đôi
+	Options        []int            `json:"options,omitempty"`
+	TargetPlayerID string           `json:"target_player_id,omitempty"`
+	Target         string           `json:"target,omitempty"`
+	TargetPosition int              `json:"target_position,omitempty"`
+	Move           string           `json:"move,omitempty"`
+	Damage         *DamageBreakdown `json:"damage,omitempty"`
+	Status         StatusKind       `json:"status,omitempty"`
+	HPLoss         int              `json:"hp_loss,omitempty"`
+	RemainingHP    int              `json:"remaining_hp,omitempty"`
+	Exp            int              `json:"exp,omitempty"`
+	Consecutive    int              `json:"consecutive,omitempty"`
+	Forfeited      bool             `json:"forfeited,omitempty"`
+	WinnerID       string           `json:"winner_id,omitempty"`
+	Reason         EndReason        `json:"reason,omitempty"`
+}
+
+// statusInflictedText - Mô tả khi Pokemon bị gây trạng thái
+var statusInflictedText = map[StatusKind]string{
+	StatusBurn:      "was burned",
+	StatusPoison:    "was poisoned",
+	StatusParalysis: "is paralyzed",
+	StatusSleep:     "fell asleep",
+}
+
+// String - Mô tả event dạng văn bản cho log và client text
+func (e Event) String() string {
+	switch e.Type {
+	case EventBattleStart:
+		if e.PlayerID == "" {
+			return fmt.Sprintf("Battle %s started", e.BattleID)
+		}
+		return fmt.Sprintf("Battle %s started, %s moves first", e.BattleID, e.PlayerID)
+	case EventTurnStart:
+		if e.PlayerID == "" {
+			return fmt.Sprintf("Turn %d: both players choose an action", e.Turn)
+		}
+		if e.Position > 0 {
+			return fmt.Sprintf("Turn %d: %s to move with position %d", e.Turn, e.PlayerID, e.Position)
+		}
+		return fmt.Sprintf("Turn %d: %s to move", e.Turn, e.PlayerID)
+	case EventActionChosen:
+		return fmt.Sprintf("%s's %s chose %s", e.PlayerID, e.Pokemon, moveLabel(e.Move))
+	case EventDamage:
+		damage := 0
+		if e.Damage != nil {
+			damage = e.Damage.Damage
+			if e.Damage.Missed {
+				return fmt.Sprintf("%s's %s used %s on %s's %s but missed",
+					e.PlayerID, e.Pokemon, moveLabel(e.Move), e.TargetPlayerID, e.Target)
+			}
+		}
+		text := fmt.Sprintf("%s's %s used %s on %s's %s for %d damage",
+			e.PlayerID, e.Pokemon, moveLabel(e.Move), e.TargetPlayerID, e.Target, damage)
+		if e.Damage != nil && e.Damage.Critical {
+			text += " (critical hit)"
+		}
+		return text
+	case EventFaint:
+		return fmt.Sprintf("%s's %s fainted", e.PlayerID, e.Pokemon)
+	case EventSwitch:
+		if e.Position > 0 {
+			return fmt.Sprintf("%s sent out %s to position %d", e.PlayerID, e.Pokemon, e.Position)
+		}
+		return fmt.Sprintf("%s sent out %s", e.PlayerID, e.Pokemon)
+	case EventSwitchRequest:
+		if e.Position > 0 {
+			return fmt.Sprintf("%s must choose a pokemon to send out to position %d", e.PlayerID, e.Position)
+		}
+		return fmt.Sprintf("%s must choose a pokemon to send out", e.PlayerID)
+	case EventStatusInflicted:
+		return fmt.Sprintf("%s's %s %s", e.PlayerID, e.Pokemon, statusInflictedText[e.Status])
+	case EventStatusDamage:
+		return fmt.Sprintf("%s's %s is hurt by %s for %d damage", e.PlayerID, e.Pokemon, e.Status, e.HPLoss)
+	case EventStatusBlocked:
+		if e.Status == StatusSleep {
+			return fmt.Sprintf("%s's %s is fast asleep", e.PlayerID, e.Pokemon)
+		}
+		return fmt.Sprintf("%s's %s is fully paralyzed and can't move", e.PlayerID, e.Pokemon)
+	case EventStatusCured:
+		if e.Status == StatusSleep {
+			return fmt.Sprintf("%s's %s woke up", e.PlayerID, e.Pokemon)
+		}
+		return fmt.Sprintf("%s's %s recovered from %s", e.PlayerID, e.Pokemon, e.Status)
+	case EventCaptured:
+		return fmt.Sprintf("%s caught the wild %s", e.PlayerID, e.Pokemon)
+	case EventCaptureFailed:
+		return fmt.Sprintf("%s tried to catch the wild %s but it broke free", e.PlayerID, e.Pokemon)
+	case EventSurrender:
+		return fmt.Sprintf("%s surrendered", e.PlayerID)
+	case EventTimeout:
+		if e.Forfeited {
+			return fmt.Sprintf("%s ran out of time %d times and forfeits", e.PlayerID, e.Consecutive)
+		}
+		return fmt.Sprintf("%s ran out of time, server used %s", e.PlayerID, moveLabel(e.Move))
+	case EventReward:
+		return fmt.Sprintf("%s's %s gained %d exp", e.PlayerID, e.Pokemon, e.Exp)
+	case EventBattleEnd:
+		if e.WinnerID == "" {
+			return "Battle ended without a winner"
+		}
+		if e.Reason != "" && e.Reason != EndReasonKO {
+			return fmt.Sprintf("%s won the battle by %s", e.WinnerID, e.Reason)
+		}
+		return fmt.Sprintf("%s won the battle", e.WinnerID)
+	}
+	return string(e.Type)
+}
+
+// Subscribe - Đăng ký nhận event của battle, trả về channel và hàm hủy đăng ký
+func (b *Battle) Subscribe(buffer int) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan Event, buffer)
+	if b.State == BattleStateFinished {
+		close(ch)
+		return ch, func() {}
+	}
+
+	b.nextSubID++
+	id := b.nextSubID
+	b.subscribers[id] = ch
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if sub, exists := b.subscribers[id]; exists {
+			delete(b.subscribers, id)
+			close(sub)
+		}
+	}
+}
+
+// GetEvents - Lấy bản sao toàn bộ event đã xảy ra
+func (b *Battle) GetEvents() []Event {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	events := make([]Event, len(b.Events))
+	copy(events, b.Events)
+	return events
+}
+
+// emit - Ghi event và đẩy tới subscriber (cần giữ b.mu)
+func (b *Battle) emit(event Event) {
+	event.Seq = len(b.Events) + 1
+	event.BattleID = b.ID
+	event.Turn = b.Turn
+	event.Time = b.clock.Now()
+	b.Events = append(b.Events, event)
+
+	for _, ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// Subscriber chậm - bỏ event thay vì chặn battle
+		}
+	}
+	if event.isPrivate() {
+		return
+	}
+	for _, ch := range b.spectators {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// closeSubscribers - Đóng mọi channel khi battle kết thúc (cần giữ b.mu)
+func (b *Battle) closeSubscribers() {
+	for id, ch := range b.subscribers {
+		close(ch)
+		delete(b.subscribers, id)
+	}
+	for id, ch := range b.spectators {
+		close(ch)
+		delete(b.spectators, id)
+	}
+}
@@ -0,0 +1,285 @@
+package pokebat
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// TurnModel - Cách các player hành động trong một lượt
+type TurnModel string
+
+const (
+	TurnModelAlternating  TurnModel = "alternating"  // Hai player lần lượt hành động
+	TurnModelSimultaneous TurnModel = "simultaneous" // Hai player cùng chọn, server xử lý theo tốc độ
+)
+
+// BattleFormat - Luật của một thể thức battle: số Pokemon, level, loài bị cấm, timer
+type BattleFormat struct {
+	Name             string    `json:"name"`
+	TeamSize         int       `json:"team_size"`
+	ActivePokemon    int       `json:"active_pokemon,omitempty"`    // Số Pokemon ra trận cùng lúc, 0 hoặc 1 là battle đơn
+	TurnModel        TurnModel `json:"turn_model,omitempty"`        // Rỗng là lần lượt
+	DamageRules      []string  `json:"damage_rules,omitempty"`      // Các luật sát thương theo thứ tự áp dụng
+	StatusConditions bool      `json:"status_conditions,omitempty"` // Bật bỏng, trúng độc, tê liệt, ngủ
+	LevelCap         int       `json:"level_cap,omitempty"`         // 0 = không giới hạn level
+	NormalizeLevel   int       `json:"normalize_level,omitempty"`   // 0 = giữ nguyên level khi vào battle
+	BannedSpecies    []string  `json:"banned_species,omitempty"`    // Tên loài hoặc tên đầy đủ bị cấm
+	BannedForms      []string  `json:"banned_forms,omitempty"`      // Từ khóa trong tên đầy đủ, ví dụ "Mega"
+	AllowDuplicates  bool      `json:"allow_duplicates"`            // Cho phép nhiều Pokemon cùng loài
+	TurnTimeout      int       `json:"turn_timeout"`                // Thời gian mỗi lượt (giây)
+	MaxTurnTimeouts  int       `json:"max_turn_timeouts"`           // Số lần hết giờ liên tiếp trước khi xử thua
+}
+
+// Các thể thức có sẵn
+var (
+	FormatStandard = &BattleFormat{
+		Name:            "standard",
+		TeamSize:        constants.MaxBattlePokemon,
+		TurnTimeout:     constants.TurnTimeout,
+		MaxTurnTimeouts: constants.MaxTurnTimeouts,
+	}
+	FormatSingle = &BattleFormat{
+		Name:            "1v1",
+		TeamSize:        1,
+		TurnTimeout:     constants.TurnTimeout,
+		MaxTurnTimeouts: constants.MaxTurnTimeouts,
+	}
+	FormatDoubles = &BattleFormat{
+		Name:            "doubles",
+		TeamSize:        4,
+		ActivePokemon:   constants.MaxActivePokemon,
+		TurnTimeout:     constants.TurnTimeout,
+		MaxTurnTimeouts: constants.MaxTurnTimeouts,
+	}
+	FormatSimultaneous = &BattleFormat{
+		Name:            "simultaneous",
+		TeamSize:        constants.MaxBattlePokemon,
+		TurnModel:       TurnModelSimultaneous,
+		TurnTimeout:     constants.TurnTimeout,
+		MaxTurnTimeouts: constants.MaxTurnTimeouts,
+	}
+	FormatCompetitive = &BattleFormat{
+		Name:             "competitive",
+		TeamSize:         constants.MaxBattlePokemon,
+		DamageRules:      []string{DamageRuleAccuracy, DamageRuleCritical, DamageRuleSameType, DamageRuleVariance},
+		StatusConditions: true,
+		TurnTimeout:      constants.TurnTimeout,
+		MaxTurnTimeouts:  constants.MaxTurnTimeouts,
+	}
+	FormatLittleCup = &BattleFormat{
+		Name:            "little_cup",
+		TeamSize:        constants.MaxBattlePokemon,
+		NormalizeLevel:  5,
+		BannedForms:     []string{"Mega"},
+		TurnTimeout:     constants.TurnTimeout,
+		MaxTurnTimeouts: constants.MaxTurnTimeouts,
+	}
+)
+
+var (
+	formats   = map[string]*BattleFormat{}
+	formatsMu sync.RWMutex
+)
+
+func init() {
+	for _, format := range []*BattleFormat{FormatStandard, FormatSingle, FormatDoubles, FormatSimultaneous, FormatCompetitive, FormatLittleCup} {
+		formats[format.Name] = format
+	}
+}
+
+// RegisterFormat - Thêm thể thức mới cho sự kiện
+func RegisterFormat(format *BattleFormat) error {
+	if err := format.Validate(); err != nil {
+		return err
+	}
+
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+
+	if _, exists := formats[format.Name]; exists {
+		return fmt.Errorf("battle format %s already registered", format.Name)
+	}
+	formats[format.Name] = format
+	return nil
+}
+
+// GetFormat - Lấy thể thức theo tên, tên rỗng là thể thức chuẩn
+func GetFormat(name string) (*BattleFormat, error) {
+	if name == "" {
+		return FormatStandard, nil
+	}
+
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	format, exists := formats[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown battle format: %s", name)
+	}
+	return format, nil
+}
+
+// ListFormats - Danh sách thể thức theo tên
+func ListFormats() []BattleFormat {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	result := make([]BattleFormat, 0, len(formats))
+	for _, format := range formats {
+		result = append(result, *format)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Validate - Kiểm tra bản thân thể thức hợp lệ
+func (f *BattleFormat) Validate() error {
+	if f.Name == "" {
+		return fmt.Errorf("battle format name is required")
+	}
+	if f.TeamSize < 1 || f.TeamSize > constants.MaxBattleTeamSize {
+		return fmt.Errorf("team size must be between 1 and %d", constants.MaxBattleTeamSize)
+	}
+	if f.ActivePokemon < 0 || f.ActivePokemon > constants.MaxActivePokemon {
+		return fmt.Errorf("active pokemon must be between 0 and %d", constants.MaxActivePokemon)
+	}
+	if f.TeamSize < f.activeSlots() {
+		return fmt.Errorf("format %s needs at least %d pokemon", f.Name, f.activeSlots())
+	}
+	switch f.TurnModel {
+	case "", TurnModelAlternating:
+	case TurnModelSimultaneous:
+		if f.activeSlots() > 1 {
+			return fmt.Errorf("simultaneous turns are only supported with one active pokemon")
+		}
+	default:
+		return fmt.Errorf("unknown turn model: %s", f.TurnModel)
+	}
+	if _, err := getDamageModifiers(f.DamageRules); err != nil {
+		return err
+	}
+	if f.LevelCap < 0 || f.LevelCap > constants.MaxLevel ||
+		f.NormalizeLevel < 0 || f.NormalizeLevel > constants.MaxLevel {
+		return fmt.Errorf(constants.ErrInvalidLevel)
+	}
+	if f.TurnTimeout < 1 || f.MaxTurnTimeouts < 1 {
+		return fmt.Errorf("invalid timer settings for format %s", f.Name)
+	}
+	return nil
+}
+
+// activeSlots - Số vị trí ra trận mỗi bên
+func (f *BattleFormat) activeSlots() int {
+	if f.ActivePokemon < 1 {
+		return 1
+	}
+	return f.ActivePokemon
+}
+
+// turnModel - Cách hành động trong lượt, mặc định là lần lượt
+func (f *BattleFormat) turnModel() TurnModel {
+	if f.TurnModel == "" {
+		return TurnModelAlternating
+	}
+	return f.TurnModel
+}
+
+// ValidateTeam - Kiểm tra team theo luật của thể thức
+func (f *BattleFormat) ValidateTeam(team []*models.Pokemon) error {
+	if len(team) != f.TeamSize {
+		return fmt.Errorf("%s: format %s requires %d pokemon, got %d",
+			constants.ErrInvalidBattleTeam, f.Name, f.TeamSize, len(team))
+	}
+
+	for i, pokemon := range team {
+		if err := f.checkPokemon(team[:i], pokemon); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPokemon - Kiểm tra một Pokemon có được thêm vào các Pokemon đã chọn trước đó
+func (f *BattleFormat) checkPokemon(chosen []*models.Pokemon, pokemon *models.Pokemon) error {
+	if pokemon == nil || !pokemon.IsAlive() {
+		return fmt.Errorf("%s: pokemon is not available for battle", constants.ErrInvalidBattleTeam)
+	}
+	if f.LevelCap > 0 && pokemon.Level > f.LevelCap {
+		return fmt.Errorf("%s: %s is above the level cap of %d",
+			constants.ErrInvalidBattleTeam, pokemon.FullName, f.LevelCap)
+	}
+	if f.isBanned(pokemon) {
+		return fmt.Errorf("%s: %s is banned in format %s",
+			constants.ErrInvalidBattleTeam, pokemon.FullName, f.Name)
+	}
+	if !f.AllowDuplicates {
+		for _, other := range chosen {
+			if other.Number == pokemon.Number {
+				return fmt.Errorf("%s: duplicate species %s", constants.ErrInvalidBattleTeam, pokemon.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// isBanned - Pokemon thuộc loài hoặc dạng bị cấm
+func (f *BattleFormat) isBanned(pokemon *models.Pokemon) bool {
+	fullName := strings.ToLower(pokemon.FullName)
+	for _, banned := range f.BannedSpecies {
+		banned = strings.ToLower(banned)
+		if banned == fullName || banned == strings.ToLower(pokemon.Name) || banned == pokemon.Number {
+			return true
+		}
+	}
+	for _, form := range f.BannedForms {
+		if strings.Contains(fullName, strings.ToLower(form)) {
+			return true
+		}
+	}
+	return false
+}
+
+// prepareTeam - Bản sao của team với level đã chuẩn hóa theo thể thức
+func (f *BattleFormat) prepareTeam(team []*models.Pokemon) []*models.Pokemon {
+	prepared := make([]*models.Pokemon, len(team))
+	for i, pokemon := range team {
+		pokemonCopy := pokemon.Clone()
+		if f.NormalizeLevel > 0 {
+			pokemonCopy.SetLevel(f.NormalizeLevel)
+		}
+		prepared[i] = pokemonCopy
+	}
+	return prepared
+}
+
+// playerTeam - Lấy team đã chọn của player
+func playerTeam(player *models.Player) ([]*models.Pokemon, error) {
+	numbers := player.GetBattleTeam()
+	if len(numbers) == 0 {
+		return nil, fmt.Errorf("%s: no battle team selected", constants.ErrInvalidBattleTeam)
+	}
+
+	team := make([]*models.Pokemon, len(numbers))
+	for i, num := range numbers {
+		pokemon, err := player.GetPokemon(num)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pokemon %s: %v", num, err)
+		}
+		team[i] = pokemon
+	}
+	return team, nil
+}
+
+// ValidateTeam - Kiểm tra team đã chọn của player theo thể thức
+func ValidateTeam(player *models.Player, format *BattleFormat) error {
+	team, err := playerTeam(player)
+	if err != nil {
+		return err
+	}
+	return format.ValidateTeam(team)
+}
@@ -0,0 +1,87 @@
+package pokebat
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/clock"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/database"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/testutil"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.RunInTempDir(m))
+}
+
+var testStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// newTestTeam - Team gồm các loài theo tên ở level chỉ định, đã học đòn theo learnset
+func newTestTeam(t *testing.T, level int, names ...string) []*models.Pokemon {
+	t.Helper()
+	pokedex, err := database.GetPokedex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	team := make([]*models.Pokemon, 0, len(names))
+	for _, name := range names {
+		entry, err := pokedex.Find(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pokemon, err := models.NewPokemon(entry.ToMap(), level, 0.75)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := pokemon.LearnMoves(); err != nil {
+			t.Fatal(err)
+		}
+		team = append(team, pokemon)
+	}
+	return team
+}
+
+// newTestBattle - Battle theo lượt giữa "p1" và "p2" chạy theo đồng hồ giả, chưa bắt đầu
+func newTestBattle(t *testing.T, turnTimeout time.Duration, maxTimeouts int) (*Battle, *clock.Fake) {
+	t.Helper()
+	battle, err := NewBattleWithTeams("battle-test", "p1", newTestTeam(t, 50, "Bulbasaur", "Charmander", "Squirtle"),
+		"p2", newTestTeam(t, 50, "Pidgey", "Rattata", "Pikachu"), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clk := clock.NewFake(testStart)
+	if err := battle.SetClock(clk); err != nil {
+		t.Fatal(err)
+	}
+	if err := battle.SetTurnTimer(turnTimeout, maxTimeouts); err != nil {
+		t.Fatal(err)
+	}
+	return battle, clk
+}
+
+// drainEvents - Lấy các event đang chờ trong channel mà không chặn
+func drainEvents(events <-chan Event) []Event {
+	var drained []Event
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return drained
+			}
+			drained = append(drained, event)
+		default:
+			return drained
+		}
+	}
+}
+
+func eventsOfType(events []Event, eventType EventType) []Event {
+	var matched []Event
+	for _, event := range events {
+		if event.Type == eventType {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
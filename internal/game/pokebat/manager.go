@@ -0,0 +1,683 @@
+package pokebat
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/clock"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+type ChallengeState string
+
+const (
+	ChallengeStatePending  ChallengeState = "pending"
+	ChallengeStateAccepted ChallengeState = "accepted"
+	ChallengeStateDeclined ChallengeState = "declined"
+	ChallengeStateExpired  ChallengeState = "expired"
+)
+
+// Challenge - Lời thách đấu trực tiếp giữa hai player
+type Challenge struct {
+	ID           string
+	ChallengerID string
+	OpponentID   string
+	Format       string
+	State        ChallengeState
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+// queueEntry - Một player đang chờ trong hàng đợi xếp hạng
+type queueEntry struct {
+	player   *models.Player
+	format   *BattleFormat
+	strength int
+	joinedAt time.Time
+}
+
+// Manager - Quản lý toàn bộ battle, lời thách đấu và hàng đợi ghép cặp
+type Manager struct {
+	battles       map[string]*Battle
+	battlePlayers map[string][]*models.Player // battleID -> players tham gia
+	playerBattles map[string]string           // playerID -> battleID
+	challenges    map[string]*Challenge
+	queue         []*queueEntry
+	npcs          map[string]*NPCTrainer
+	agentStops    map[string]func() // battleID -> hàm dừng agent của NPC
+	recorded      map[string]bool   // battleID đã cập nhật rating và lịch sử đấu
+	nextBattleID  uint64
+	nextChallenge uint64
+	// OnBattleCreated - Hook báo cho tầng network khi có battle mới (challenge hoặc hàng đợi)
+	OnBattleCreated func(*Battle)
+	// OnChallenge - Hook báo khi có lời thách đấu mới (ví dụ để dừng chế độ tự động di chuyển)
+	OnChallenge func(Challenge)
+	mu          sync.Mutex
+	ticker      clock.Ticker
+	clock       clock.Clock
+	done        chan struct{}
+	stopOnce    sync.Once
+}
+
+func NewManager() *Manager {
+	return NewManagerWithClock(clock.Real())
+}
+
+// NewManagerWithClock - Tạo manager với hạn thách đấu, ghép cặp và các battle được tạo chạy theo clk
+func NewManagerWithClock(clk clock.Clock) *Manager {
+	manager := &Manager{
+		clock:         clk,
+		battles:       make(map[string]*Battle),
+		battlePlayers: make(map[string][]*models.Player),
+		playerBattles: make(map[string]string),
+		challenges:    make(map[string]*Challenge),
+		queue:         make([]*queueEntry, 0),
+		npcs:          make(map[string]*NPCTrainer),
+		agentStops:    make(map[string]func()),
+		recorded:      make(map[string]bool),
+		done:          make(chan struct{}),
+	}
+
+	manager.startMaintenanceRoutine()
+	return manager
+}
+
+// Now - Thời điểm hiện tại theo clock của manager (hạn thách đấu, thời gian chờ trong hàng đợi)
+func (m *Manager) Now() time.Time {
+	return m.clock.Now()
+}
+
+// Challenge - Gửi lời thách đấu tới một player khác theo thể thức chỉ định
+func (m *Manager) Challenge(challenger, opponent *models.Player, format *BattleFormat) (*Challenge, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if challenger.GetID() == opponent.GetID() {
+		return nil, fmt.Errorf("cannot challenge yourself")
+	}
+	if err := m.checkAvailable(challenger); err != nil {
+		return nil, err
+	}
+	if err := m.checkAvailable(opponent); err != nil {
+		return nil, err
+	}
+	if err := ValidateTeam(challenger, format); err != nil {
+		return nil, err
+	}
+
+	m.nextChallenge++
+	now := m.clock.Now()
+	challenge := &Challenge{
+		ID:           fmt.Sprintf("challenge-%d", m.nextChallenge),
+		ChallengerID: challenger.GetID(),
+		OpponentID:   opponent.GetID(),
+		Format:       format.Name,
+		State:        ChallengeStatePending,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(time.Duration(constants.ChallengeTimeout) * time.Second),
+	}
+	m.challenges[challenge.ID] = challenge
+
+	copyChallenge := *challenge
+	if m.OnChallenge != nil {
+		go m.OnChallenge(copyChallenge)
+	}
+	return &copyChallenge, nil
+}
+
+// AcceptChallenge - Chấp nhận lời thách đấu và tạo battle
+func (m *Manager) AcceptChallenge(challengeID string, challenger, opponent *models.Player) (*Battle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	challenge, err := m.getPendingChallenge(challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.OpponentID != opponent.GetID() || challenge.ChallengerID != challenger.GetID() {
+		return nil, fmt.Errorf("challenge %s does not belong to these players", challengeID)
+	}
+
+	format, err := GetFormat(challenge.Format)
+	if err != nil {
+		return nil, err
+	}
+	battle, err := m.createBattle(challenger, opponent, format)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge.State = ChallengeStateAccepted
+	delete(m.challenges, challengeID)
+	return battle, nil
+}
+
+// DeclineChallenge - Từ chối (hoặc hủy) lời thách đấu
+func (m *Manager) DeclineChallenge(challengeID string, playerID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	challenge, err := m.getPendingChallenge(challengeID)
+	if err != nil {
+		return err
+	}
+	if challenge.OpponentID != playerID && challenge.ChallengerID != playerID {
+		return fmt.Errorf("challenge %s does not belong to player %s", challengeID, playerID)
+	}
+
+	challenge.State = ChallengeStateDeclined
+	delete(m.challenges, challengeID)
+	return nil
+}
+
+// GetChallengesFor - Lấy các lời thách đấu đang chờ liên quan tới player
+func (m *Manager) GetChallengesFor(playerID string) []Challenge {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]Challenge, 0)
+	for _, challenge := range m.challenges {
+		if challenge.OpponentID == playerID || challenge.ChallengerID == playerID {
+			result = append(result, *challenge)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result
+}
+
+// JoinQueue - Đưa player vào hàng đợi xếp hạng của một thể thức
+func (m *Manager) JoinQueue(player *models.Player, format *BattleFormat) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkAvailable(player); err != nil {
+		return err
+	}
+	if err := ValidateTeam(player, format); err != nil {
+		return err
+	}
+	for _, entry := range m.queue {
+		if entry.player.GetID() == player.GetID() {
+			return fmt.Errorf(constants.ErrAlreadyQueued)
+		}
+	}
+
+	m.queue = append(m.queue, &queueEntry{
+		player:   player,
+		format:   format,
+		strength: teamStrength(player),
+		joinedAt: m.clock.Now(),
+	})
+	return nil
+}
+
+// LeaveQueue - Rời hàng đợi xếp hạng
+func (m *Manager) LeaveQueue(playerID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, entry := range m.queue {
+		if entry.player.GetID() == playerID {
+			m.queue = append(m.queue[:i], m.queue[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("player %s is not in queue", playerID)
+}
+
+// RegisterNPC - Đăng ký NPC trainer để thách đấu hoặc lấp chỗ trong hàng đợi
+func (m *Manager) RegisterNPC(npc *NPCTrainer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.npcs[npc.ID]; exists {
+		return fmt.Errorf("npc %s already registered", npc.ID)
+	}
+	m.npcs[npc.ID] = npc
+	return nil
+}
+
+// CreateBattle - Tạo battle trực tiếp giữa hai player (giải đấu, sự kiện do server sắp xếp)
+func (m *Manager) CreateBattle(p1, p2 *models.Player, format *BattleFormat) (*Battle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if p1.GetID() == p2.GetID() {
+		return nil, fmt.Errorf("players must be different")
+	}
+	return m.createBattle(p1, p2, format)
+}
+
+// StartNPCBattle - Tạo battle giữa player và một NPC trainer
+func (m *Manager) StartNPCBattle(player *models.Player, npcID string, format *BattleFormat) (*Battle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	npc, exists := m.npcs[npcID]
+	if !exists {
+		return nil, fmt.Errorf("npc %s not found", npcID)
+	}
+	return m.createNPCBattle(player, npc, format)
+}
+
+// Forfeit - Player rời server giữa chừng: battle đang diễn ra bị xử thua, battle chưa bắt đầu bị hủy.
+// Player được giải phóng ngay để lần đăng nhập sau không bị chặn.
+func (m *Manager) Forfeit(playerID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	battleID, exists := m.playerBattles[playerID]
+	if !exists {
+		return
+	}
+	battle := m.battles[battleID]
+	battle.mu.Lock()
+	switch battle.State {
+	case BattleStateActive:
+		battle.recorder.record(battle.Turn, playerID, ReplayActionSurrender, "")
+		battle.surrender(playerID)
+	case BattleStateWaiting:
+		battle.expire()
+	}
+	battle.mu.Unlock()
+
+	m.recordBattle(battleID)
+	m.releasePlayers(battleID)
+}
+
+// GetBattle - Lấy battle theo ID
+func (m *Manager) GetBattle(battleID string) (*Battle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	battle, exists := m.battles[battleID]
+	if !exists {
+		return nil, fmt.Errorf(constants.ErrBattleNotFound)
+	}
+	return battle, nil
+}
+
+// GetPlayerBattle - Lấy battle mà player đang tham gia
+func (m *Manager) GetPlayerBattle(playerID string) (*Battle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	battleID, exists := m.playerBattles[playerID]
+	if !exists {
+		return nil, fmt.Errorf(constants.ErrBattleNotFound)
+	}
+	return m.battles[battleID], nil
+}
+
+// ListLiveBattles - Liệt kê các battle chưa kết thúc, battle mới nhất trước
+func (m *Manager) ListLiveBattles() []BattleSummary {
+	m.mu.Lock()
+	battles := make([]*Battle, 0, len(m.battles))
+	for _, battle := range m.battles {
+		battles = append(battles, battle)
+	}
+	m.mu.Unlock()
+
+	result := make([]BattleSummary, 0, len(battles))
+	for _, battle := range battles {
+		summary := battle.Summary()
+		if summary.State != BattleStateFinished {
+			result = append(result, summary)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].StartTime.After(result[j].StartTime)
+	})
+	return result
+}
+
+// Cleanup - Dừng routine và kết thúc các battle còn lại, gọi nhiều lần không lỗi
+func (m *Manager) Cleanup() {
+	m.stopOnce.Do(func() { close(m.done) })
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for battleID, battle := range m.battles {
+		battle.mu.Lock()
+		if battle.State != BattleStateFinished {
+			battle.expire()
+		}
+		battle.mu.Unlock()
+		m.settleBattle(battleID)
+	}
+	m.queue = m.queue[:0]
+}
+
+// createBattle - Tạo battle mới và đánh dấu hai player đang bận (cần giữ m.mu)
+func (m *Manager) createBattle(p1, p2 *models.Player, format *BattleFormat) (*Battle, error) {
+	if err := m.checkAvailable(p1); err != nil {
+		return nil, err
+	}
+	if err := m.checkAvailable(p2); err != nil {
+		return nil, err
+	}
+
+	m.nextBattleID++
+	battleID := fmt.Sprintf("battle-%d", m.nextBattleID)
+
+	battle, err := NewBattle(battleID, p1, p2, m.clock.Now().UnixNano(), format)
+	if err != nil {
+		return nil, err
+	}
+	battle.useClock(m.clock)
+
+	if err := p1.SetCurrentBattle(battleID); err != nil {
+		return nil, err
+	}
+	if err := p2.SetCurrentBattle(battleID); err != nil {
+		p1.SetCurrentBattle("")
+		return nil, err
+	}
+
+	m.battles[battleID] = battle
+	m.battlePlayers[battleID] = []*models.Player{p1, p2}
+	m.playerBattles[p1.GetID()] = battleID
+	m.playerBattles[p2.GetID()] = battleID
+	m.removeFromQueue(p1.GetID())
+	m.removeFromQueue(p2.GetID())
+
+	if m.OnBattleCreated != nil {
+		go m.OnBattleCreated(battle)
+	}
+	return battle, nil
+}
+
+// createNPCBattle - Tạo battle với NPC, NPC sẵn sàng ngay và tự hành động (cần giữ m.mu)
+func (m *Manager) createNPCBattle(player *models.Player, npc *NPCTrainer, format *BattleFormat) (*Battle, error) {
+	if err := m.checkAvailable(player); err != nil {
+		return nil, err
+	}
+
+	bp, err := setupBattlePlayer(player, format)
+	if err != nil {
+		return nil, err
+	}
+	npcTeam, err := npc.teamFor(format)
+	if err != nil {
+		return nil, err
+	}
+
+	m.nextBattleID++
+	battleID := fmt.Sprintf("battle-%d", m.nextBattleID)
+	battle := newBattle(battleID, bp, &BattlePlayer{ID: npc.ID, Team: format.prepareTeam(npcTeam), IsReady: true},
+		m.clock.Now().UnixNano(), format)
+	battle.useClock(m.clock)
+
+	if err := player.SetCurrentBattle(battleID); err != nil {
+		return nil, err
+	}
+
+	m.battles[battleID] = battle
+	m.battlePlayers[battleID] = []*models.Player{player}
+	m.playerBattles[player.GetID()] = battleID
+	m.agentStops[battleID] = RunAgent(battle, npc.ID, npc.Agent)
+	m.removeFromQueue(player.GetID())
+
+	if m.OnBattleCreated != nil {
+		go m.OnBattleCreated(battle)
+	}
+	return battle, nil
+}
+
+// checkAvailable - Mỗi player chỉ được tham gia một battle tại một thời điểm
+func (m *Manager) checkAvailable(player *models.Player) error {
+	if _, busy := m.playerBattles[player.GetID()]; busy {
+		return fmt.Errorf(constants.ErrBattleInProgress)
+	}
+	if player.IsInBattle() {
+		return fmt.Errorf(constants.ErrBattleInProgress)
+	}
+	return nil
+}
+
+func (m *Manager) getPendingChallenge(challengeID string) (*Challenge, error) {
+	challenge, exists := m.challenges[challengeID]
+	if !exists {
+		return nil, fmt.Errorf(constants.ErrChallengeNotFound)
+	}
+	if m.clock.Now().After(challenge.ExpiresAt) {
+		challenge.State = ChallengeStateExpired
+		delete(m.challenges, challengeID)
+		return nil, fmt.Errorf("challenge %s has expired", challengeID)
+	}
+	return challenge, nil
+}
+
+func (m *Manager) removeFromQueue(playerID string) {
+	for i, entry := range m.queue {
+		if entry.player.GetID() == playerID {
+			m.queue = append(m.queue[:i], m.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+func (m *Manager) startMaintenanceRoutine() {
+	m.ticker = m.clock.NewTicker(time.Duration(constants.MatchmakingInterval) * time.Second)
+
+	go func() {
+		for {
+			select {
+			case <-m.ticker.C():
+				m.runMaintenance()
+			case <-m.done:
+				m.ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// runMaintenance - Hết hạn lời thách đấu, ghép cặp hàng đợi và dọn battle
+func (m *Manager) runMaintenance() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	m.expireChallenges(now)
+	m.matchQueue(now)
+	m.reapBattles(now)
+}
+
+func (m *Manager) expireChallenges(now time.Time) {
+	for id, challenge := range m.challenges {
+		if now.After(challenge.ExpiresAt) {
+			challenge.State = ChallengeStateExpired
+			delete(m.challenges, id)
+		}
+	}
+}
+
+// matchQueue - Ghép các player có sức mạnh team gần nhau, người chờ lâu được nới rộng
+func (m *Manager) matchQueue(now time.Time) {
+	sort.Slice(m.queue, func(i, j int) bool {
+		return m.queue[i].joinedAt.Before(m.queue[j].joinedAt)
+	})
+
+	matched := make(map[int]bool)
+	pairs := make([][2]*queueEntry, 0)
+	for i, entry := range m.queue {
+		if matched[i] {
+			continue
+		}
+
+		best := -1
+		bestDiff := math.MaxFloat64
+		for j := i + 1; j < len(m.queue); j++ {
+			if matched[j] {
+				continue
+			}
+			other := m.queue[j]
+			if other.format != entry.format {
+				continue
+			}
+			diff := strengthDiff(entry.strength, other.strength)
+			if diff > queueTolerance(entry, now) || diff > queueTolerance(other, now) {
+				continue
+			}
+			if diff < bestDiff {
+				best, bestDiff = j, diff
+			}
+		}
+
+		if best >= 0 {
+			matched[i] = true
+			matched[best] = true
+			pairs = append(pairs, [2]*queueEntry{entry, m.queue[best]})
+		}
+	}
+
+	remaining := make([]*queueEntry, 0, len(m.queue))
+	for i, entry := range m.queue {
+		if !matched[i] {
+			remaining = append(remaining, entry)
+		}
+	}
+	m.queue = remaining
+
+	for _, pair := range pairs {
+		if _, err := m.createBattle(pair[0].player, pair[1].player, pair[0].format); err != nil {
+			// Player không còn hợp lệ (ví dụ team thay đổi) bị bỏ khỏi hàng đợi, người còn hợp lệ được xếp lại
+			for _, entry := range pair {
+				if m.checkAvailable(entry.player) == nil && ValidateTeam(entry.player, entry.format) == nil {
+					m.queue = append(m.queue, entry)
+				}
+			}
+		}
+	}
+
+	m.fillQueueWithNPCs(now)
+}
+
+// fillQueueWithNPCs - Player chờ quá lâu sẽ được ghép với NPC có sức mạnh gần nhất
+func (m *Manager) fillQueueWithNPCs(now time.Time) {
+	if len(m.npcs) == 0 {
+		return
+	}
+
+	waiting := make([]*queueEntry, 0)
+	for _, entry := range m.queue {
+		if now.Sub(entry.joinedAt) >= time.Duration(constants.QueueNPCFillDelay)*time.Second {
+			waiting = append(waiting, entry)
+		}
+	}
+
+	for _, entry := range waiting {
+		var best *NPCTrainer
+		bestDiff := math.MaxFloat64
+		for _, npc := range m.npcs {
+			strength, err := npc.strength(entry.format)
+			if err != nil {
+				continue
+			}
+			diff := strengthDiff(entry.strength, strength)
+			if diff < bestDiff || (diff == bestDiff && best != nil && npc.ID < best.ID) {
+				best, bestDiff = npc, diff
+			}
+		}
+		if best == nil {
+			continue
+		}
+		if _, err := m.createNPCBattle(entry.player, best, entry.format); err != nil {
+			m.removeFromQueue(entry.player.GetID())
+		}
+	}
+}
+
+// reapBattles - Kết thúc battle quá hạn và xóa battle đã kết thúc
+func (m *Manager) reapBattles(now time.Time) {
+	for battleID, battle := range m.battles {
+		battle.mu.Lock()
+		switch battle.State {
+		case BattleStateWaiting:
+			if now.Sub(battle.StartTime) > time.Duration(constants.ReadyTimeout)*time.Second {
+				battle.expire()
+			}
+		case BattleStateActive:
+			if now.Sub(battle.StartTime) > time.Duration(constants.BattleTimeout)*time.Second {
+				battle.expire()
+			}
+		}
+		finished := battle.State == BattleStateFinished
+		endTime := battle.EndTime
+		battle.mu.Unlock()
+
+		if !finished {
+			continue
+		}
+		m.recordBattle(battleID)
+		m.releasePlayers(battleID)
+		if now.Sub(endTime) > time.Duration(constants.FinishedBattleRetention)*time.Second {
+			m.settleBattle(battleID)
+		}
+	}
+}
+
+// releasePlayers - Giải phóng player khỏi battle đã kết thúc
+func (m *Manager) releasePlayers(battleID string) {
+	for _, player := range m.battlePlayers[battleID] {
+		if m.playerBattles[player.GetID()] != battleID {
+			continue
+		}
+		delete(m.playerBattles, player.GetID())
+		player.SetCurrentBattle("")
+	}
+}
+
+// settleBattle - Cập nhật rating, giải phóng player, lưu replay và xóa battle khỏi manager
+func (m *Manager) settleBattle(battleID string) {
+	m.recordBattle(battleID)
+	m.releasePlayers(battleID)
+	if stop, exists := m.agentStops[battleID]; exists {
+		stop()
+		delete(m.agentStops, battleID)
+	}
+	if battle, exists := m.battles[battleID]; exists && len(battle.GetEvents()) > 0 {
+		filename := filepath.Join(constants.ReplayDir, fmt.Sprintf("%s.json", battleID))
+		if err := battle.SaveReplay(filename); err != nil {
+			log.Printf("Failed to save replay for battle %s: %v", battleID, err)
+		}
+	}
+	delete(m.battlePlayers, battleID)
+	delete(m.battles, battleID)
+	delete(m.recorded, battleID)
+}
+
+// teamStrength - Sức mạnh team = tổng chỉ số Total của các Pokemon trong team
+func teamStrength(player *models.Player) int {
+	total := 0
+	for _, num := range player.GetBattleTeam() {
+		if pokemon, err := player.GetPokemon(num); err == nil {
+			total += pokemon.CurrentStats.Total
+		}
+	}
+	return total
+}
+
+func strengthDiff(a, b int) float64 {
+	larger := math.Max(float64(a), float64(b))
+	if larger == 0 {
+		return 0
+	}
+	return math.Abs(float64(a-b)) / larger
+}
+
+func queueTolerance(entry *queueEntry, now time.Time) float64 {
+	waited := now.Sub(entry.joinedAt).Seconds()
+	tolerance := constants.QueueBaseTolerance + waited*constants.QueueToleranceGrowth
+	return math.Min(tolerance, constants.QueueMaxTolerance)
+}
@@ -0,0 +1,9 @@
+package pokebat
+
+import "testing"
+
+func TestManagerCleanupTwice(t *testing.T) {
+	manager := NewManager()
+	manager.Cleanup()
+	manager.Cleanup()
+}
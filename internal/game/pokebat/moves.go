@@ -0,0 +1,109 @@
+package pokebat
+
+import (
+	"fmt"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// isGenericMove - Đòn thường hoặc đòn đặc biệt, dùng khi Pokemon chưa học đòn nào hoặc đã hết PP
+func isGenericMove(move string) bool {
+	return move == constants.NormalAttackType || move == constants.SpecialAttackType
+}
+
+// validateMove - Pokemon còn đòn đã học với PP thì phải chọn một trong số đó,
+// nếu không chỉ được dùng đòn generic
+func validateMove(attacker *models.Pokemon, move string) error {
+	if !attacker.HasUsableMoves() {
+		if !isGenericMove(move) {
+			return fmt.Errorf("invalid move type")
+		}
+		return nil
+	}
+
+	learned := attacker.FindMove(move)
+	if learned == nil {
+		return fmt.Errorf("%s: %s", constants.ErrUnknownMove, move)
+	}
+	if learned.PP <= 0 {
+		return fmt.Errorf("%s: %s", constants.ErrNoPPLeft, learned.Name)
+	}
+	return nil
+}
+
+// usePP - Trừ một PP của đòn đã học (chỉ trên bản sao của battle)
+func usePP(attacker *models.Pokemon, move string) {
+	if learned := attacker.FindMove(move); learned != nil && learned.PP > 0 {
+		learned.PP--
+	}
+}
+
+// usableMoves - Tên các đòn đã học còn PP
+func usableMoves(pokemon models.Pokemon) []string {
+	moves := make([]string, 0, len(pokemon.Moves))
+	for _, move := range pokemon.Moves {
+		if move.PP > 0 {
+			moves = append(moves, move.Name)
+		}
+	}
+	return moves
+}
+
+// computeMoveDamage - Công thức gốc cho đòn đã học: chỉ số theo loại đòn, hệ số theo type của đòn
+// với từng type của đối thủ, nhân với power so với BaseMovePower
+func computeMoveDamage(attacker, defender *models.Pokemon, move *models.Move) DamageBreakdown {
+	breakdown := DamageBreakdown{
+		MoveType:       move.Name,
+		AttackType:     move.Type,
+		Category:       move.Category,
+		Power:          move.Power,
+		Accuracy:       move.Accuracy,
+		TypeMultiplier: 1.0,
+	}
+
+	if move.Category == constants.PhysicalMoveCategory {
+		breakdown.AttackStat = attacker.CurrentStats.Attack
+		breakdown.DefenseStat = defender.CurrentStats.Defense
+	} else {
+		breakdown.AttackStat = attacker.CurrentStats.SpecialAtk
+		breakdown.DefenseStat = defender.CurrentStats.SpecialDef
+	}
+
+	effectiveness := constants.TypeEffectiveness[move.Type]
+	for _, defenderType := range defender.GetTypes() {
+		if multiplier, exists := effectiveness[defenderType]; exists {
+			breakdown.TypeMultiplier *= multiplier
+		}
+	}
+
+	base := int(float64(breakdown.AttackStat)*breakdown.TypeMultiplier) - breakdown.DefenseStat
+	breakdown.RawDamage = base * move.Power / constants.BaseMovePower
+
+	// Đối thủ miễn nhiễm type của đòn thì không nhận sát thương
+	switch {
+	case breakdown.TypeMultiplier == 0:
+		breakdown.Damage = 0
+	case breakdown.RawDamage < constants.MinDamage:
+		breakdown.Damage = constants.MinDamage
+	default:
+		breakdown.Damage = breakdown.RawDamage
+	}
+	return breakdown
+}
+
+// isSpecialMove - Đòn đặc biệt generic hoặc đòn đã học thuộc loại special
+func isSpecialMove(breakdown DamageBreakdown) bool {
+	if breakdown.Category != "" {
+		return breakdown.Category == constants.SpecialMoveCategory
+	}
+	return breakdown.MoveType == constants.SpecialAttackType
+}
+
+// moveLabel - Tên đòn để hiển thị: đòn generic kèm chữ "attack", đòn đã học giữ nguyên tên
+func moveLabel(move string) string {
+	if isGenericMove(move) {
+		return move + " attack"
+	}
+	return move
+}
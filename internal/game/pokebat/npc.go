@@ -0,0 +1,143 @@
+package pokebat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/database"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// NPCTrainer - Trainer do server điều khiển bằng BattleAgent
+type NPCTrainer struct {
+	ID    string
+	Name  string
+	Team  []models.Pokemon // Team mẫu, mỗi battle dùng một bản sao đầy máu
+	Agent BattleAgent
+}
+
+func NewNPCTrainer(id, name string, team []*models.Pokemon, agent BattleAgent) (*NPCTrainer, error) {
+	if id == "" {
+		return nil, fmt.Errorf("npc id is required")
+	}
+	if len(team) == 0 {
+		return nil, fmt.Errorf("npc %s has no pokemon", id)
+	}
+	if agent == nil {
+		return nil, fmt.Errorf("npc %s has no agent", id)
+	}
+
+	npc := &NPCTrainer{
+		ID:    id,
+		Name:  name,
+		Team:  make([]models.Pokemon, len(team)),
+		Agent: agent,
+	}
+	for i, pokemon := range team {
+		npc.Team[i] = *pokemon.Clone()
+	}
+	return npc, nil
+}
+
+// rosterFile - Cấu trúc file npcs.json
+type rosterFile struct {
+	NPCs []struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Agent string `json:"agent"` // random, greedy hoặc lookahead
+		Team  []struct {
+			Pokemon string `json:"pokemon"`
+			Level   int    `json:"level"`
+		} `json:"team"`
+	} `json:"npcs"`
+}
+
+// LoadNPCRoster - Đọc danh sách NPC trainer từ file JSON; Pokemon được tạo theo Pokedex với EV mặc định
+// và học đòn theo learnset ở level của mình. Agent ngẫu nhiên của NPC thứ i dùng seed+i.
+func LoadNPCRoster(path string, seed int64) ([]*NPCTrainer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read npc roster: %v", err)
+	}
+	var file rosterFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse npc roster: %v", err)
+	}
+
+	pokedex, err := database.GetPokedex()
+	if err != nil {
+		return nil, err
+	}
+
+	roster := make([]*NPCTrainer, 0, len(file.NPCs))
+	for i, entry := range file.NPCs {
+		agent, err := NewAgent(entry.Agent, seed+int64(i))
+		if err != nil {
+			return nil, fmt.Errorf("npc %s: %v", entry.ID, err)
+		}
+
+		team := make([]*models.Pokemon, 0, len(entry.Team))
+		for _, member := range entry.Team {
+			species, err := pokedex.Find(member.Pokemon)
+			if err != nil {
+				return nil, fmt.Errorf("npc %s: %v", entry.ID, err)
+			}
+			pokemon, err := models.NewPokemon(species.ToMap(), member.Level, constants.DefaultEV)
+			if err != nil {
+				return nil, fmt.Errorf("npc %s: %s: %v", entry.ID, member.Pokemon, err)
+			}
+			if err := pokemon.LearnMoves(); err != nil {
+				return nil, err
+			}
+			team = append(team, pokemon)
+		}
+
+		npc, err := NewNPCTrainer(entry.ID, entry.Name, team, agent)
+		if err != nil {
+			return nil, err
+		}
+		roster = append(roster, npc)
+	}
+	return roster, nil
+}
+
+// newTeam - Tạo bản sao team cho một battle mới
+func (n *NPCTrainer) newTeam() []*models.Pokemon {
+	team := make([]*models.Pokemon, len(n.Team))
+	for i := range n.Team {
+		team[i] = n.Team[i].Clone()
+	}
+	return team
+}
+
+// teamFor - Chọn các Pokemon đầu tiên của team mẫu hợp lệ với thể thức
+func (n *NPCTrainer) teamFor(format *BattleFormat) ([]*models.Pokemon, error) {
+	team := make([]*models.Pokemon, 0, format.TeamSize)
+	for _, pokemon := range n.newTeam() {
+		if len(team) == format.TeamSize {
+			break
+		}
+		if format.checkPokemon(team, pokemon) == nil {
+			team = append(team, pokemon)
+		}
+	}
+	if err := format.ValidateTeam(team); err != nil {
+		return nil, fmt.Errorf("npc %s cannot play format %s: %v", n.ID, format.Name, err)
+	}
+	return team, nil
+}
+
+// strength - Sức mạnh của team NPC dùng cho thể thức, cùng thước đo với player
+func (n *NPCTrainer) strength(format *BattleFormat) (int, error) {
+	team, err := n.teamFor(format)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, pokemon := range team {
+		total += pokemon.CurrentStats.Total
+	}
+	return total, nil
+}
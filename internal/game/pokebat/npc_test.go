@@ -0,0 +1,29 @@
+package pokebat
+
+import "testing"
+
+// TestNPCStrengthUsesFormatTeam - Sức mạnh NPC chỉ tính các Pokemon được chọn cho thể thức
+func TestNPCStrengthUsesFormatTeam(t *testing.T) {
+	team := newTestTeam(t, 30, "Pidgey", "Rattata", "Pikachu", "Bulbasaur")
+	npc, err := NewNPCTrainer("npc-test", "Tester", team, NewGreedyAgent())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		format *BattleFormat
+		want   int
+	}{
+		{FormatSingle, team[0].CurrentStats.Total},
+		{FormatStandard, team[0].CurrentStats.Total + team[1].CurrentStats.Total + team[2].CurrentStats.Total},
+	}
+	for _, test := range tests {
+		got, err := npc.strength(test.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("%s strength = %d, want %d", test.format.Name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package pokebat
+
+import (
+	"log"
+	"math"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// ExpectedScore - Xác suất thắng kỳ vọng theo Elo của rating so với đối thủ
+func ExpectedScore(rating, opponent int) float64 {
+	return 1 / (1 + math.Pow(10, float64(opponent-rating)/400))
+}
+
+// UpdateRating - Rating mới sau trận đấu, score là 1 (thắng), 0.5 (hòa) hoặc 0 (thua)
+func UpdateRating(rating, opponent int, score float64) int {
+	change := constants.EloKFactor * (score - ExpectedScore(rating, opponent))
+	return rating + int(math.Round(change))
+}
+
+// matchResult - Kết quả một battle từ góc nhìn của một player
+func matchResult(battle *Battle, playerID string) (models.MatchOutcome, float64) {
+	switch battle.WinnerID {
+	case "":
+		return models.MatchDraw, 0.5
+	case playerID:
+		return models.MatchWin, 1
+	}
+	return models.MatchLoss, 0
+}
+
+// recordBattle - Cập nhật rating và lịch sử đấu của player sau battle (cần giữ m.mu)
+func (m *Manager) recordBattle(battleID string) {
+	battle, exists := m.battles[battleID]
+	if !exists || m.recorded[battleID] {
+		return
+	}
+	m.recorded[battleID] = true
+
+	battle.mu.RLock()
+	defer battle.mu.RUnlock()
+
+	// Battle chưa từng bắt đầu không được tính
+	if battle.State != BattleStateFinished || battle.Turn == 0 {
+		return
+	}
+
+	players := make(map[string]*models.Player)
+	for _, player := range m.battlePlayers[battleID] {
+		players[player.GetID()] = player
+	}
+
+	// Chỉ tính rating khi cả hai bên là player thật
+	rated := len(players) == 2
+	ratings := make(map[string]int)
+	for _, bp := range []*BattlePlayer{battle.Player1, battle.Player2} {
+		if player, exists := players[bp.ID]; exists {
+			ratings[bp.ID] = player.GetRating()
+		}
+	}
+
+	for _, bp := range []*BattlePlayer{battle.Player1, battle.Player2} {
+		player, exists := players[bp.ID]
+		if !exists {
+			continue
+		}
+		opponentID := battle.getDefendingPlayer(bp.ID).ID
+		outcome, score := matchResult(battle, bp.ID)
+
+		match := models.MatchRecord{
+			BattleID:     battleID,
+			OpponentID:   opponentID,
+			Outcome:      outcome,
+			EndReason:    string(battle.EndReason),
+			Rated:        rated,
+			RatingBefore: ratings[bp.ID],
+			RatingAfter:  ratings[bp.ID],
+			Turns:        battle.Turn,
+			EndTime:      battle.EndTime,
+		}
+		if rated {
+			match.RatingAfter = UpdateRating(ratings[bp.ID], ratings[opponentID], score)
+		}
+
+		if err := player.RecordMatch(match); err != nil {
+			log.Printf("Failed to record battle %s for player %s: %v", battleID, bp.ID, err)
+		}
+	}
+}
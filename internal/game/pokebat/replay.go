@@ -0,0 +1,291 @@
+package pokebat
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+const ReplayVersion = 2
+
+type ReplayActionKind string
+
+const (
+	ReplayActionMove      ReplayActionKind = "move"
+	ReplayActionSwitch    ReplayActionKind = "switch"
+	ReplayActionTimeout   ReplayActionKind = "timeout"
+	ReplayActionSurrender ReplayActionKind = "surrender"
+	ReplayActionCapture   ReplayActionKind = "capture"
+	ReplayActionExpire    ReplayActionKind = "expire"
+)
+
+// ReplayAction - Một hành động được ghi lại theo đúng thứ tự xảy ra
+type ReplayAction struct {
+	Turn     int              `json:"turn"`
+	PlayerID string           `json:"player_id,omitempty"`
+	Kind     ReplayActionKind `json:"kind"`
+	Move     string           `json:"move,omitempty"`
+	Target   int              `json:"target,omitempty"`
+	Slot     int              `json:"slot,omitempty"`
+}
+
+// ReplayTeam - Team ban đầu của một player khi battle được tạo
+type ReplayTeam struct {
+	PlayerID string           `json:"player_id"`
+	Team     []models.Pokemon `json:"team"`
+}
+
+// Replay - Toàn bộ dữ liệu cần để mô phỏng lại một battle
+type Replay struct {
+	Version     int            `json:"version"`
+	BattleID    string         `json:"battle_id"`
+	Seed        int64          `json:"seed"`
+	Format      string         `json:"format,omitempty"`
+	WildID      string         `json:"wild_id,omitempty"`
+	MaxTimeouts int            `json:"max_timeouts"`
+	Player1     ReplayTeam     `json:"player1"`
+	Player2     ReplayTeam     `json:"player2"`
+	Actions     []ReplayAction `json:"actions"`
+	Events      []Event        `json:"events"`
+	WinnerID    string         `json:"winner_id,omitempty"`
+}
+
+// replayRecorder - Ghi team ban đầu và hành động của battle
+type replayRecorder struct {
+	player1 ReplayTeam
+	player2 ReplayTeam
+	actions []ReplayAction
+}
+
+func (r *replayRecorder) snapshotTeams(bp1, bp2 *BattlePlayer) {
+	r.player1 = snapshotTeam(bp1)
+	r.player2 = snapshotTeam(bp2)
+}
+
+func (r *replayRecorder) record(turn int, playerID string, kind ReplayActionKind, move string) {
+	r.actions = append(r.actions, ReplayAction{
+		Turn:     turn,
+		PlayerID: playerID,
+		Kind:     kind,
+		Move:     move,
+	})
+}
+
+func (r *replayRecorder) recordMove(turn int, playerID string, move string, target int) {
+	r.actions = append(r.actions, ReplayAction{
+		Turn:     turn,
+		PlayerID: playerID,
+		Kind:     ReplayActionMove,
+		Move:     move,
+		Target:   target,
+	})
+}
+
+func (r *replayRecorder) recordSwitch(turn int, playerID string, slot int) {
+	r.actions = append(r.actions, ReplayAction{
+		Turn:     turn,
+		PlayerID: playerID,
+		Kind:     ReplayActionSwitch,
+		Slot:     slot,
+	})
+}
+
+func snapshotTeam(bp *BattlePlayer) ReplayTeam {
+	team := ReplayTeam{PlayerID: bp.ID, Team: make([]models.Pokemon, len(bp.Team))}
+	for i, pokemon := range bp.Team {
+		team.Team[i] = *pokemon.Clone()
+	}
+	return team
+}
+
+func (t ReplayTeam) clone() []*models.Pokemon {
+	team := make([]*models.Pokemon, len(t.Team))
+	for i := range t.Team {
+		team[i] = t.Team[i].Clone()
+	}
+	return team
+}
+
+// Replay - Xuất dữ liệu replay của battle
+func (b *Battle) Replay() *Replay {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	replay := &Replay{
+		Version:     ReplayVersion,
+		BattleID:    b.ID,
+		Seed:        b.Seed,
+		Format:      b.Format,
+		WildID:      b.wildID,
+		MaxTimeouts: b.timer.maxTimeouts,
+		Player1:     b.recorder.player1,
+		Player2:     b.recorder.player2,
+		Actions:     make([]ReplayAction, len(b.recorder.actions)),
+		Events:      make([]Event, len(b.Events)),
+		WinnerID:    b.WinnerID,
+	}
+	copy(replay.Actions, b.recorder.actions)
+	copy(replay.Events, b.Events)
+	return replay
+}
+
+// SaveReplay - Lưu replay của battle vào file JSON
+func (b *Battle) SaveReplay(filename string) error {
+	return b.Replay().Save(filename)
+}
+
+// Save - Ghi replay ra file JSON
+func (r *Replay) Save(filename string) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(r, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal replay: %v", err)
+	}
+
+	tempFile := filename + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write temporary file: %v", err)
+	}
+	if err := os.Rename(tempFile, filename); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to save replay: %v", err)
+	}
+	return nil
+}
+
+// LoadReplay - Đọc replay từ file JSON
+func LoadReplay(filename string) (*Replay, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay: %v", err)
+	}
+
+	var replay Replay
+	if err := json.Unmarshal(data, &replay); err != nil {
+		return nil, fmt.Errorf("failed to parse replay: %v", err)
+	}
+	if replay.Version != ReplayVersion {
+		return nil, fmt.Errorf("unsupported replay version: %d", replay.Version)
+	}
+	return &replay, nil
+}
+
+// Simulate - Mô phỏng lại battle từ seed, team ban đầu và các hành động
+func (r *Replay) Simulate() (*Battle, error) {
+	format, err := GetFormat(r.Format)
+	if err != nil {
+		return nil, err
+	}
+	battle, err := newBattleWithTeams(r.BattleID,
+		r.Player1.PlayerID, r.Player1.clone(),
+		r.Player2.PlayerID, r.Player2.clone(), r.Seed, format)
+	if err != nil {
+		return nil, err
+	}
+
+	battle.mu.Lock()
+	defer battle.mu.Unlock()
+
+	battle.timer.manual = true
+	battle.wildID = r.WildID
+	if r.MaxTimeouts > 0 {
+		battle.timer.maxTimeouts = r.MaxTimeouts
+	}
+	battle.Player1.IsReady = true
+	battle.Player2.IsReady = true
+	if err := battle.startBattle(); err != nil {
+		return nil, err
+	}
+
+	for i, action := range r.Actions {
+		if battle.State != BattleStateActive {
+			return nil, fmt.Errorf("action %d: battle already finished", i+1)
+		}
+		if action.Kind == ReplayActionExpire {
+			battle.expire()
+			continue
+		}
+		if !battle.HasPlayer(action.PlayerID) {
+			return nil, fmt.Errorf("action %d: unknown player %s", i+1, action.PlayerID)
+		}
+
+		switch action.Kind {
+		case ReplayActionMove:
+			if !battle.canAct(action.PlayerID) {
+				return nil, fmt.Errorf("action %d: not %s's turn", i+1, action.PlayerID)
+			}
+			if err := battle.executeMove(action.PlayerID, action.Move, action.Target); err != nil {
+				return nil, fmt.Errorf("action %d: %v", i+1, err)
+			}
+			battle.timer.consecutive[action.PlayerID] = 0
+			battle.recorder.recordMove(action.Turn, action.PlayerID, action.Move, action.Target)
+			continue
+		case ReplayActionSwitch:
+			if !battle.canAct(action.PlayerID) {
+				return nil, fmt.Errorf("action %d: not %s's turn", i+1, action.PlayerID)
+			}
+			if err := battle.switchPokemon(action.PlayerID, action.Slot); err != nil {
+				return nil, fmt.Errorf("action %d: %v", i+1, err)
+			}
+			battle.timer.consecutive[action.PlayerID] = 0
+			battle.recorder.recordSwitch(action.Turn, action.PlayerID, action.Slot)
+			continue
+		case ReplayActionTimeout:
+			if !battle.canAct(action.PlayerID) {
+				return nil, fmt.Errorf("action %d: not %s's turn", i+1, action.PlayerID)
+			}
+			battle.applyTimeout(action.PlayerID)
+		case ReplayActionSurrender:
+			battle.surrender(action.PlayerID)
+		case ReplayActionCapture:
+			if !battle.canAct(action.PlayerID) {
+				return nil, fmt.Errorf("action %d: not %s's turn", i+1, action.PlayerID)
+			}
+			if _, err := battle.tryCapture(action.PlayerID); err != nil {
+				return nil, fmt.Errorf("action %d: %v", i+1, err)
+			}
+		default:
+			return nil, fmt.Errorf("action %d: unknown kind %q", i+1, action.Kind)
+		}
+		battle.recorder.record(action.Turn, action.PlayerID, action.Kind, action.Move)
+	}
+
+	return battle, nil
+}
+
+// Verify - Mô phỏng lại và kiểm tra kết quả khớp với replay đã ghi
+func (r *Replay) Verify() (*Battle, error) {
+	battle, err := r.Simulate()
+	if err != nil {
+		return nil, err
+	}
+
+	if battle.WinnerID != r.WinnerID {
+		return battle, fmt.Errorf("winner mismatch: recorded %q, simulated %q", r.WinnerID, battle.WinnerID)
+	}
+	if len(battle.Events) != len(r.Events) {
+		return battle, fmt.Errorf("event count mismatch: recorded %d, simulated %d",
+			len(r.Events), len(battle.Events))
+	}
+	for i := range r.Events {
+		if !sameEvent(r.Events[i], battle.Events[i]) {
+			return battle, fmt.Errorf("event %d mismatch: recorded %q, simulated %q",
+				i+1, r.Events[i].String(), battle.Events[i].String())
+		}
+	}
+	return battle, nil
+}
+
+// sameEvent - So sánh hai event, bỏ qua thời điểm phát
+func sameEvent(a, b Event) bool {
+	a.Time, b.Time = time.Time{}, time.Time{}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,83 @@
+package pokebat
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// playSeededReplay - Chơi hết một battle có luật sát thương ngẫu nhiên giữa hai agent ngẫu nhiên
+// có seed cố định, lưu replay ra file rồi đọc lại
+func playSeededReplay(t *testing.T) (*Battle, *Replay) {
+	t.Helper()
+	battle, err := newBattleWithTeams("replay-test",
+		"p1", newTestTeam(t, 30, "Bulbasaur", "Charmander", "Squirtle"),
+		"p2", newTestTeam(t, 30, "Pidgey", "Rattata", "Pikachu"), 42, FormatCompetitive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := PlayAgents(battle, NewRandomAgent(1), NewRandomAgent(2), 1000); err != nil {
+		t.Fatal(err)
+	}
+	if battle.GetWinner() == "" {
+		t.Fatal("battle finished without a winner")
+	}
+
+	filename := filepath.Join(t.TempDir(), "replay.json")
+	if err := battle.SaveReplay(filename); err != nil {
+		t.Fatal(err)
+	}
+	replay, err := LoadReplay(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return battle, replay
+}
+
+func TestReplayVerifiesRecordedBattle(t *testing.T) {
+	battle, replay := playSeededReplay(t)
+
+	simulated, err := replay.Verify()
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if simulated.WinnerID != battle.WinnerID || len(simulated.Events) != len(battle.Events) {
+		t.Errorf("simulated winner %s with %d events, recorded %s with %d events",
+			simulated.WinnerID, len(simulated.Events), battle.WinnerID, len(battle.Events))
+	}
+}
+
+func TestReplayVerifyDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(t *testing.T, replay *Replay)
+	}{
+		{"damage event", func(t *testing.T, replay *Replay) {
+			for i := range replay.Events {
+				if replay.Events[i].Damage != nil {
+					replay.Events[i].Damage.Damage++
+					return
+				}
+			}
+			t.Fatal("replay has no damage event")
+		}},
+		{"winner", func(t *testing.T, replay *Replay) {
+			if replay.WinnerID == replay.Player1.PlayerID {
+				replay.WinnerID = replay.Player2.PlayerID
+			} else {
+				replay.WinnerID = replay.Player1.PlayerID
+			}
+		}},
+		{"seed", func(t *testing.T, replay *Replay) {
+			replay.Seed++
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, replay := playSeededReplay(t)
+			test.tamper(t, replay)
+			if _, err := replay.Verify(); err == nil {
+				t.Error("tampered replay passed verification")
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package pokebat
+
+import "fmt"
+
+// canAct - Player được phép hành động lúc này
+func (b *Battle) canAct(playerID string) bool {
+	if b.TurnModel != TurnModelSimultaneous {
+		return b.CurrentTurn == playerID
+	}
+	if !b.HasPlayer(playerID) {
+		return false
+	}
+	// Đang chờ thay Pokemon bị hạ - chỉ player phải đổi được hành động
+	if b.Player1.PendingSwitch || b.Player2.PendingSwitch {
+		return b.getAttackingPlayer(playerID).PendingSwitch
+	}
+	return true
+}
+
+// waitingOn - Battle đang chờ hành động của player
+func (b *Battle) waitingOn(playerID string) bool {
+	if !b.canAct(playerID) {
+		return false
+	}
+	_, submitted := b.submitted[playerID]
+	return !submitted
+}
+
+// submit - Khóa hành động của player trong lượt đánh đồng thời, xử lý lượt khi cả hai đã chọn
+func (b *Battle) submit(playerID string, action Action) error {
+	if _, exists := b.submitted[playerID]; exists {
+		return fmt.Errorf("action already submitted")
+	}
+	b.submitted[playerID] = action
+	if len(b.submitted) == 2 {
+		b.resolveRound()
+	}
+	return nil
+}
+
+// resolveRound - Xử lý hành động của cả hai player: đổi Pokemon trước, sau đó tấn công theo tốc độ
+func (b *Battle) resolveRound() {
+	actions := b.submitted
+	b.submitted = make(map[string]Action)
+	b.stopTurnTimer()
+
+	// Ghi lại Pokemon đã chọn đòn - nếu bị hạ hoặc bị thay trước khi tới lượt thì đòn bị hủy
+	attackers := make(map[string]int)
+	for playerID := range actions {
+		attackers[playerID] = b.getAttackingPlayer(playerID).Active[0]
+	}
+
+	for _, playerID := range b.roundOrder(actions) {
+		if b.State != BattleStateActive {
+			return
+		}
+		player := b.getAttackingPlayer(playerID)
+		action := actions[playerID]
+
+		if action.Kind == ActionSwitch {
+			player.setActive(0, action.SwitchTo)
+			b.emit(Event{Type: EventSwitch, PlayerID: playerID, Pokemon: player.Team[action.SwitchTo].Name, Slot: action.SwitchTo})
+			continue
+		}
+
+		attacker := player.activePokemon(0)
+		if player.Active[0] != attackers[playerID] || attacker == nil || !attacker.IsAlive() {
+			continue
+		}
+		targetSlot, defender := b.resolveTarget(playerID, action.Target)
+		if defender == nil || !defender.IsAlive() {
+			continue
+		}
+		if b.statusBlocksAction(player, 0) {
+			continue
+		}
+		b.attack(playerID, 0, attacker, targetSlot, defender, action.Move)
+	}
+
+	// Sát thương trạng thái cuối lượt
+	if b.statusEnabled {
+		for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+			if b.State != BattleStateActive {
+				return
+			}
+			b.applyStatusDamage(player, 0)
+		}
+	}
+	if b.State != BattleStateActive {
+		return
+	}
+
+	b.LastMoveTime = b.clock.Now()
+	if b.Player1.PendingSwitch || b.Player2.PendingSwitch {
+		// Chờ player chọn Pokemon thay thế trước khi sang lượt mới
+		b.startTurnTimer()
+		return
+	}
+	b.beginTurn()
+}
+
+// roundOrder - Thứ tự xử lý hành động trong lượt: đổi Pokemon trước, sau đó theo tốc độ,
+// bằng tốc độ thì ngẫu nhiên
+func (b *Battle) roundOrder(actions map[string]Action) []string {
+	first, second := b.Player1, b.Player2
+	firstSwitch := actions[first.ID].Kind == ActionSwitch
+	secondSwitch := actions[second.ID].Kind == ActionSwitch
+
+	swap := false
+	switch {
+	case firstSwitch != secondSwitch:
+		swap = secondSwitch
+	default:
+		firstSpeed := first.speedOf(first.Active[0])
+		secondSpeed := second.speedOf(second.Active[0])
+		if firstSpeed != secondSpeed {
+			swap = secondSpeed > firstSpeed
+		} else {
+			swap = b.rng.Float64() < 0.5
+		}
+	}
+
+	if swap {
+		first, second = second, first
+	}
+	return []string{first.ID, second.ID}
+}
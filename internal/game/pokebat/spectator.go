@@ -0,0 +1,76 @@
+package pokebat
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+)
+
+// BattleSummary - Thông tin tóm tắt của một battle đang diễn ra
+type BattleSummary struct {
+	BattleID   string      `json:"battle_id"`
+	Player1ID  string      `json:"player1_id"`
+	Player2ID  string      `json:"player2_id"`
+	State      BattleState `json:"state"`
+	Turn       int         `json:"turn"`
+	Spectators int         `json:"spectators"`
+	StartTime  time.Time   `json:"start_time"`
+}
+
+// isPrivate - Event chỉ dành cho người chơi, không gửi cho người xem
+func (e Event) isPrivate() bool {
+	// Lựa chọn Pokemon thay thế chưa quyết định sẽ lộ thông tin team
+	return e.Type == EventSwitchRequest
+}
+
+// Spectate - Đăng ký xem battle, trả về các event công khai đã xảy ra,
+// channel event tiếp theo và hàm hủy đăng ký
+func (b *Battle) Spectate(buffer int) ([]Event, <-chan Event, func(), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.State == BattleStateFinished {
+		return nil, nil, nil, fmt.Errorf(constants.ErrBattleFinished)
+	}
+	if len(b.spectators) >= constants.MaxSpectators {
+		return nil, nil, nil, fmt.Errorf(constants.ErrSpectatorLimit)
+	}
+
+	history := make([]Event, 0, len(b.Events))
+	for _, event := range b.Events {
+		if !event.isPrivate() {
+			history = append(history, event)
+		}
+	}
+
+	ch := make(chan Event, buffer)
+	b.nextSubID++
+	id := b.nextSubID
+	b.spectators[id] = ch
+
+	return history, ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if sub, exists := b.spectators[id]; exists {
+			delete(b.spectators, id)
+			close(sub)
+		}
+	}, nil
+}
+
+// Summary - Lấy thông tin tóm tắt của battle
+func (b *Battle) Summary() BattleSummary {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return BattleSummary{
+		BattleID:   b.ID,
+		Player1ID:  b.Player1.ID,
+		Player2ID:  b.Player2.ID,
+		State:      b.State,
+		Turn:       b.Turn,
+		Spectators: len(b.spectators),
+		StartTime:  b.StartTime,
+	}
+}
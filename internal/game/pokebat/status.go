@@ -0,0 +1,153 @@
+package pokebat
+
+import (
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// StatusKind - Trạng thái bất lợi của Pokemon trong battle
+type StatusKind string
+
+const (
+	StatusNone      StatusKind = ""
+	StatusBurn      StatusKind = "burn"
+	StatusPoison    StatusKind = "poison"
+	StatusParalysis StatusKind = "paralysis"
+	StatusSleep     StatusKind = "sleep"
+)
+
+// StatusCondition - Trạng thái của một Pokemon trong team, chỉ tồn tại trong battle
+type StatusCondition struct {
+	Status     StatusKind `json:"status,omitempty"`
+	SleepTurns int        `json:"sleep_turns,omitempty"` // Số lần hành động còn bị bỏ qua do ngủ
+}
+
+// statusByType - Type của đòn đặc biệt và trạng thái mà đòn có thể gây ra
+var statusByType = map[string]StatusKind{
+	"Fire":     StatusBurn,
+	"Poison":   StatusPoison,
+	"Electric": StatusParalysis,
+	"Psychic":  StatusSleep,
+}
+
+// initStatus - Team vào battle không có trạng thái, HP hiện tại là HP tối đa trong battle
+func (bp *BattlePlayer) initStatus() {
+	bp.Status = make([]StatusCondition, len(bp.Team))
+	bp.maxHP = make([]int, len(bp.Team))
+	for i, pokemon := range bp.Team {
+		bp.maxHP[i] = pokemon.CurrentStats.HP
+	}
+}
+
+// speedOf - Tốc độ hiệu lực của Pokemon thứ index, tê liệt làm giảm tốc độ
+func (bp *BattlePlayer) speedOf(index int) int {
+	speed := bp.Team[index].CurrentStats.Speed
+	if bp.Status[index].Status == StatusParalysis {
+		speed = int(float64(speed) * constants.ParalysisSpeedFactor)
+	}
+	return speed
+}
+
+// tryInflictStatus - Đòn đặc biệt trúng có thể gây trạng thái theo type của đòn
+func (b *Battle) tryInflictStatus(playerID string, targetSlot int, attacker *models.Pokemon, breakdown DamageBreakdown) {
+	if !b.statusEnabled || breakdown.Missed || !isSpecialMove(breakdown) {
+		return
+	}
+	status, exists := statusByType[moveElement(attacker, &breakdown)]
+	if !exists {
+		return
+	}
+
+	defender := b.getDefendingPlayer(playerID)
+	index := defender.Active[targetSlot]
+	condition := &defender.Status[index]
+	if condition.Status != StatusNone || !defender.Team[index].IsAlive() {
+		return
+	}
+	if b.rng.Float64() >= constants.StatusInflictChance {
+		return
+	}
+
+	condition.Status = status
+	if status == StatusSleep {
+		condition.SleepTurns = 1 + b.rng.Intn(constants.MaxSleepTurns)
+	}
+	b.emit(Event{
+		Type:     EventStatusInflicted,
+		PlayerID: defender.ID,
+		Pokemon:  defender.Team[index].Name,
+		Position: b.position(targetSlot),
+		Status:   status,
+	})
+}
+
+// statusBlocksAction - Pokemon đang ngủ hoặc bị tê liệt có thể mất lượt hành động
+func (b *Battle) statusBlocksAction(player *BattlePlayer, slot int) bool {
+	index := player.Active[slot]
+	condition := &player.Status[index]
+	event := Event{
+		Type:     EventStatusBlocked,
+		PlayerID: player.ID,
+		Pokemon:  player.Team[index].Name,
+		Position: b.position(slot),
+		Status:   condition.Status,
+	}
+
+	switch condition.Status {
+	case StatusSleep:
+		if condition.SleepTurns > 0 {
+			condition.SleepTurns--
+			b.emit(event)
+			return true
+		}
+		condition.Status = StatusNone
+		event.Type = EventStatusCured
+		b.emit(event)
+	case StatusParalysis:
+		if b.rng.Float64() < constants.ParalysisSkipChance {
+			b.emit(event)
+			return true
+		}
+	}
+	return false
+}
+
+// applyStatusDamage - Bỏng và trúng độc gây sát thương mỗi lượt, có thể hạ Pokemon
+func (b *Battle) applyStatusDamage(player *BattlePlayer, slot int) error {
+	pokemon := player.activePokemon(slot)
+	if pokemon == nil || !pokemon.IsAlive() {
+		return nil
+	}
+	index := player.Active[slot]
+
+	var divisor int
+	switch player.Status[index].Status {
+	case StatusBurn:
+		divisor = constants.BurnDamageDivisor
+	case StatusPoison:
+		divisor = constants.PoisonDamageDivisor
+	default:
+		return nil
+	}
+
+	damage := player.maxHP[index] / divisor
+	if damage < constants.MinDamage {
+		damage = constants.MinDamage
+	}
+	pokemon.CurrentStats.HP -= damage
+	b.emit(Event{
+		Type:        EventStatusDamage,
+		PlayerID:    player.ID,
+		Pokemon:     pokemon.Name,
+		Position:    b.position(slot),
+		Status:      player.Status[index].Status,
+		HPLoss:      damage,
+		RemainingHP: pokemon.CurrentStats.HP,
+	})
+
+	if !pokemon.IsAlive() {
+		b.emit(Event{Type: EventFaint, PlayerID: player.ID, Pokemon: pokemon.Name, Position: b.position(slot)})
+		return b.handleFaintedPokemon(b.getDefendingPlayer(player.ID).ID, slot)
+	}
+	return nil
+}
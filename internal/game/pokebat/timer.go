@@ -0,0 +1,217 @@
+package pokebat
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/clock"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/random"
+)
+
+// turnTimer - Trạng thái đồng hồ đếm ngược theo lượt của battle
+type turnTimer struct {
+	timeout     time.Duration
+	maxTimeouts int
+	deadline    time.Time
+	seq         uint64
+	timer       clock.Timer
+	consecutive map[string]int
+	manual      bool // Không chạy timer thật (dùng khi replay/mô phỏng)
+}
+
+func newTurnTimer(format *BattleFormat) turnTimer {
+	return turnTimer{
+		timeout:     time.Duration(format.TurnTimeout) * time.Second,
+		maxTimeouts: format.MaxTurnTimeouts,
+		consecutive: make(map[string]int),
+	}
+}
+
+// SetTurnTimer - Cấu hình thời gian mỗi lượt và số lần hết giờ tối đa cho battle
+func (b *Battle) SetTurnTimer(timeout time.Duration, maxTimeouts int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.State != BattleStateWaiting {
+		return fmt.Errorf("battle already started")
+	}
+	if timeout <= 0 {
+		return fmt.Errorf("invalid turn timeout: %v", timeout)
+	}
+	if maxTimeouts < 1 {
+		return fmt.Errorf("invalid max timeouts: %d", maxTimeouts)
+	}
+
+	b.timer.timeout = timeout
+	b.timer.maxTimeouts = maxTimeouts
+	return nil
+}
+
+// SetClock - Cho battle (đồng hồ lượt, thời hạn battle, thời gian event) chạy theo clk, gọi trước khi battle bắt đầu
+func (b *Battle) SetClock(clk clock.Clock) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.State != BattleStateWaiting {
+		return fmt.Errorf("battle already started")
+	}
+	b.useClock(clk)
+	return nil
+}
+
+// useClock - Đổi clock của battle chưa bắt đầu (cần giữ b.mu hoặc battle chưa được chia sẻ)
+func (b *Battle) useClock(clk clock.Clock) {
+	b.clock = clk
+	b.StartTime = clk.Now()
+	b.LastMoveTime = b.StartTime
+}
+
+// SetRandomSource - Thay nguồn ngẫu nhiên của battle, gọi trước khi battle bắt đầu.
+// Replay chỉ tái hiện được battle dùng nguồn mặc định tạo từ Seed.
+func (b *Battle) SetRandomSource(rng random.Source) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.State != BattleStateWaiting {
+		return fmt.Errorf("battle already started")
+	}
+	b.rng = rng
+	return nil
+}
+
+// GetTurnDeadline - Lấy thời điểm hết hạn của lượt hiện tại
+func (b *Battle) GetTurnDeadline() time.Time {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.timer.deadline
+}
+
+// startTurnTimer - Khởi động lại đồng hồ cho lượt mới (cần giữ b.mu)
+func (b *Battle) startTurnTimer() {
+	b.stopTurnTimer()
+	if b.State != BattleStateActive {
+		return
+	}
+
+	b.timer.seq++
+	seq := b.timer.seq
+	b.timer.deadline = b.clock.Now().Add(b.timer.timeout)
+	if b.timer.manual {
+		return
+	}
+	b.timer.timer = b.clock.AfterFunc(b.timer.timeout, func() {
+		b.handleTurnTimeout(seq)
+	})
+}
+
+// stopTurnTimer - Dừng đồng hồ hiện tại (cần giữ b.mu)
+func (b *Battle) stopTurnTimer() {
+	if b.timer.timer != nil {
+		b.timer.timer.Stop()
+		b.timer.timer = nil
+	}
+	b.timer.deadline = time.Time{}
+}
+
+// handleTurnTimeout - Xử lý khi timer của một lượt kích hoạt
+func (b *Battle) handleTurnTimeout(seq uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Lượt đã kết thúc trước khi timer kích hoạt
+	if b.State != BattleStateActive || seq != b.timer.seq {
+		return
+	}
+
+	if b.TurnModel == TurnModelSimultaneous {
+		// Mọi player chưa chọn hành động đều bị tính hết giờ
+		waiting := make([]string, 0, 2)
+		for _, player := range []*BattlePlayer{b.Player1, b.Player2} {
+			if b.waitingOn(player.ID) {
+				waiting = append(waiting, player.ID)
+			}
+		}
+		for _, playerID := range waiting {
+			if b.State != BattleStateActive {
+				return
+			}
+			b.recorder.record(b.Turn, playerID, ReplayActionTimeout, "")
+			b.applyTimeout(playerID)
+		}
+		return
+	}
+
+	playerID := b.CurrentTurn
+	b.recorder.record(b.Turn, playerID, ReplayActionTimeout, "")
+	b.applyTimeout(playerID)
+}
+
+// applyTimeout - Server tự đánh thay player, hoặc xử thua nếu hết giờ quá nhiều lần (cần giữ b.mu)
+func (b *Battle) applyTimeout(playerID string) {
+	b.timer.consecutive[playerID]++
+	event := Event{
+		Type:        EventTimeout,
+		PlayerID:    playerID,
+		Consecutive: b.timer.consecutive[playerID],
+	}
+
+	if event.Consecutive >= b.timer.maxTimeouts {
+		event.Forfeited = true
+		b.emit(event)
+		b.getAttackingPlayer(playerID).HasSurrender = true
+		b.endBattle(b.getDefendingPlayer(playerID).ID, EndReasonTimeout)
+		return
+	}
+
+	// Phải chọn Pokemon thay thế trước - server chọn Pokemon đầu tiên còn sống
+	player := b.getAttackingPlayer(playerID)
+	event.Move = b.randomMove(b.timeoutAttacker(player))
+	b.emit(event)
+
+	if b.TurnModel == TurnModelSimultaneous {
+		if !player.PendingSwitch {
+			b.submit(playerID, Action{Kind: ActionAttack, Move: event.Move})
+		} else if options := player.aliveOptions(); len(options) > 0 {
+			b.switchPokemon(playerID, options[0])
+		}
+		return
+	}
+	if player.PendingSwitch {
+		if options := player.aliveOptions(); len(options) > 0 {
+			b.switchPokemon(playerID, options[0])
+		}
+		if b.ActiveSlots > 1 {
+			// Battle đôi - đổi bắt buộc đã chuyển sang vị trí hành động kế tiếp
+			return
+		}
+	}
+	if err := b.executeMove(playerID, event.Move, 0); err != nil {
+		// Không thể đánh thay - chuyển lượt để battle không bị treo
+		b.switchTurn()
+	}
+}
+
+// timeoutAttacker - Pokemon sẽ đánh thay player: Pokemon thay thế đầu tiên nếu đang phải đổi
+func (b *Battle) timeoutAttacker(player *BattlePlayer) *models.Pokemon {
+	if player.PendingSwitch {
+		if options := player.aliveOptions(); len(options) > 0 {
+			return player.Team[options[0]]
+		}
+	}
+	return player.activePokemon(b.CurrentSlot)
+}
+
+// randomMove - Chọn ngẫu nhiên một đòn đã học còn PP, hoặc giữa đòn thường và đòn đặc biệt
+func (b *Battle) randomMove(pokemon *models.Pokemon) string {
+	if pokemon != nil {
+		if moves := usableMoves(*pokemon); len(moves) > 0 {
+			return moves[b.rng.Intn(len(moves))]
+		}
+	}
+	if b.rng.Float64() < 0.5 {
+		return constants.NormalAttackType
+	}
+	return constants.SpecialAttackType
+}
@@ -0,0 +1,103 @@
+package pokebat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+)
+
+// startTestBattle - Cả hai player sẵn sàng, trả về player được đi trước
+func startTestBattle(t *testing.T, battle *Battle) string {
+	t.Helper()
+	for _, playerID := range []string{"p1", "p2"} {
+		if err := battle.SetPlayerReady(playerID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, playerID := range []string{"p1", "p2"} {
+		view, err := battle.View(playerID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if view.IsMyTurn {
+			return playerID
+		}
+	}
+	t.Fatal("no player can act after the battle started")
+	return ""
+}
+
+func TestTurnTimeoutFiresAtDeadline(t *testing.T) {
+	const timeout = 10 * time.Second
+	battle, clk := newTestBattle(t, timeout, 3)
+	events, unsubscribe := battle.Subscribe(constants.EventBufferSize)
+	defer unsubscribe()
+
+	first := startTestBattle(t, battle)
+	deadline := battle.GetTurnDeadline()
+	if want := testStart.Add(timeout); !deadline.Equal(want) {
+		t.Fatalf("turn deadline = %v, want %v", deadline, want)
+	}
+
+	clk.Advance(timeout - time.Nanosecond)
+	if timeouts := eventsOfType(drainEvents(events), EventTimeout); len(timeouts) != 0 {
+		t.Fatalf("turn timed out %v before its deadline", time.Nanosecond)
+	}
+
+	clk.Advance(time.Nanosecond)
+	timeouts := eventsOfType(drainEvents(events), EventTimeout)
+	if len(timeouts) != 1 {
+		t.Fatalf("%d timeout events at the deadline, want 1", len(timeouts))
+	}
+	if timeouts[0].PlayerID != first || !timeouts[0].Time.Equal(deadline) || timeouts[0].Consecutive != 1 {
+		t.Errorf("timeout event = %+v, want player %s at %v", timeouts[0], first, deadline)
+	}
+
+	// Server đánh thay nên lượt chuyển sang đối thủ với hạn mới tính từ lúc hết giờ
+	if next := battle.GetTurnDeadline(); !next.Equal(deadline.Add(timeout)) {
+		t.Errorf("next turn deadline = %v, want %v", next, deadline.Add(timeout))
+	}
+}
+
+func TestTurnTimerResetByAction(t *testing.T) {
+	const timeout = 10 * time.Second
+	battle, clk := newTestBattle(t, timeout, 3)
+	events, unsubscribe := battle.Subscribe(constants.EventBufferSize)
+	defer unsubscribe()
+
+	first := startTestBattle(t, battle)
+	clk.Advance(timeout / 2)
+	view, err := battle.View(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := battle.SubmitAction(first, NewGreedyAgent().ChooseAction(view)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Hạn cũ đã qua nhưng lượt mới chưa hết giờ
+	clk.Advance(timeout / 2)
+	if timeouts := eventsOfType(drainEvents(events), EventTimeout); len(timeouts) != 0 {
+		t.Fatalf("timer of an answered turn fired: %+v", timeouts)
+	}
+	clk.Advance(timeout / 2)
+	if timeouts := eventsOfType(drainEvents(events), EventTimeout); len(timeouts) != 1 || timeouts[0].PlayerID == first {
+		t.Errorf("timeouts after the second turn deadline = %+v, want one for the opponent", timeouts)
+	}
+}
+
+func TestRepeatedTimeoutsForfeit(t *testing.T) {
+	const timeout = 5 * time.Second
+	battle, clk := newTestBattle(t, timeout, 2)
+	startTestBattle(t, battle)
+
+	// Không ai hành động: mỗi player hết giờ hai lần liên tiếp, người đi trước bị xử thua trước
+	for i := 0; i < 4 && battle.GetState() == BattleStateActive; i++ {
+		clk.Advance(timeout)
+	}
+	winnerID, reason := battle.GetResult()
+	if battle.GetState() != BattleStateFinished || reason != EndReasonTimeout || winnerID == "" {
+		t.Errorf("battle state %v, winner %q, reason %q; want finished by timeout", battle.GetState(), winnerID, reason)
+	}
+}
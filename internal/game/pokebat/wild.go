@@ -0,0 +1,104 @@
+package pokebat
+
+import (
+	"fmt"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// StartWildBattle - Tạo battle theo thể thức format giữa team của player và một Pokemon hoang dã
+// do agent ngẫu nhiên điều khiển. Pokemon hoang dã được sao chép, bản gốc trên grid không bị ảnh hưởng.
+func (m *Manager) StartWildBattle(player *models.Player, wild *models.Pokemon, format *BattleFormat) (*Battle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkAvailable(player); err != nil {
+		return nil, err
+	}
+	if wild == nil || !wild.IsAlive() {
+		return nil, fmt.Errorf("pokemon is not available for battle")
+	}
+
+	bp, err := setupBattlePlayer(player, format)
+	if err != nil {
+		return nil, err
+	}
+
+	m.nextBattleID++
+	battleID := fmt.Sprintf("battle-%d", m.nextBattleID)
+	wildID := constants.WildPlayerPrefix + battleID
+	seed := m.clock.Now().UnixNano()
+	battle := newBattle(battleID, bp, &BattlePlayer{ID: wildID, Team: []*models.Pokemon{wild.Clone()}, IsReady: true},
+		seed, format)
+	battle.wildID = wildID
+	battle.useClock(m.clock)
+
+	if err := player.SetCurrentBattle(battleID); err != nil {
+		return nil, err
+	}
+
+	m.battles[battleID] = battle
+	m.battlePlayers[battleID] = []*models.Player{player}
+	m.playerBattles[player.GetID()] = battleID
+	m.agentStops[battleID] = RunAgent(battle, wildID, NewRandomAgent(seed))
+	m.removeFromQueue(player.GetID())
+
+	if m.OnBattleCreated != nil {
+		go m.OnBattleCreated(battle)
+	}
+	return battle, nil
+}
+
+// IsWild - Battle với Pokemon hoang dã
+func (b *Battle) IsWild() bool {
+	return b.wildID != ""
+}
+
+// TryCapture - Dùng lượt để bắt Pokemon hoang dã. Trả về true nếu bắt được (battle kết thúc).
+func (b *Battle) TryCapture(playerID string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.State != BattleStateActive {
+		return false, fmt.Errorf("battle not active")
+	}
+	if !b.canAct(playerID) {
+		return false, fmt.Errorf("not your turn")
+	}
+
+	captured, err := b.tryCapture(playerID)
+	if err != nil {
+		return false, err
+	}
+	b.recorder.record(b.Turn, playerID, ReplayActionCapture, "")
+	b.timer.consecutive[playerID] = 0
+	return captured, nil
+}
+
+// tryCapture - Xác suất bắt tăng theo tỉ lệ HP Pokemon hoang dã đã mất; thất bại thì mất lượt
+func (b *Battle) tryCapture(playerID string) (bool, error) {
+	if !b.IsWild() || playerID == b.wildID {
+		return false, fmt.Errorf("only the trainer in a wild battle can capture")
+	}
+	if b.getAttackingPlayer(playerID).PendingSwitch {
+		return false, fmt.Errorf("must switch pokemon first")
+	}
+
+	wild := b.getDefendingPlayer(playerID)
+	pokemon := wild.Team[0]
+	chance := constants.WildCaptureBaseChance
+	if maxHP := wild.maxHP[0]; maxHP > 0 {
+		chance += constants.WildCaptureWeakenBonus * (1 - float64(pokemon.CurrentStats.HP)/float64(maxHP))
+	}
+
+	if b.rng.Float64() < chance {
+		b.emit(Event{Type: EventCaptured, PlayerID: playerID, Pokemon: pokemon.Name})
+		return true, b.endBattle(playerID, EndReasonCapture)
+	}
+
+	b.emit(Event{Type: EventCaptureFailed, PlayerID: playerID, Pokemon: pokemon.Name})
+	b.switchTurn()
+	b.LastMoveTime = b.clock.Now()
+	return false, nil
+}
@@ -0,0 +1,175 @@
+package pokecat
+
+import (
+	"sort"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// Rect - Vùng chữ nhật trong world, góc trên trái (X, Y). Khi world wrap-around,
+// vùng vượt mép sẽ tiếp tục ở mép đối diện.
+type Rect struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// VisiblePokemon - Pokemon hoang dã nhìn thấy trong vùng
+type VisiblePokemon struct {
+	X       int             `json:"x"`
+	Y       int             `json:"y"`
+	Pokemon *models.Pokemon `json:"pokemon"`
+}
+
+// VisiblePlayer - Player nhìn thấy trong vùng
+type VisiblePlayer struct {
+	X        int    `json:"x"`
+	Y        int    `json:"y"`
+	PlayerID string `json:"player_id"`
+}
+
+// View - Pokemon và player trong một vùng, sắp xếp theo hàng, cột rồi ID
+type View struct {
+	Area    Rect             `json:"area"`
+	Pokemon []VisiblePokemon `json:"pokemon"`
+	Players []VisiblePlayer  `json:"players"`
+}
+
+// span - Đoạn [from, to) trên một trục, không vượt mép world
+type span struct {
+	from int
+	to   int
+}
+
+// QueryRect - Lấy Pokemon và player trong vùng area. Chỉ duyệt các chunk giao với vùng
+// và các ô đã được tạo, nên chi phí không phụ thuộc vào diện tích vùng trống.
+func (g *Grid) QueryRect(area Rect) View {
+	view := View{Area: area, Pokemon: make([]VisiblePokemon, 0), Players: make([]VisiblePlayer, 0)}
+	for _, ys := range g.spans(area.Y, area.Height, g.height) {
+		for _, xs := range g.spans(area.X, area.Width, g.width) {
+			g.collect(xs, ys, func(x, y int) bool { return true }, &view)
+		}
+	}
+	sortView(&view)
+	return view
+}
+
+// QueryRadius - Lấy Pokemon và player cách (x, y) không quá radius ô (khoảng cách Euclid),
+// có tính wrap-around
+func (g *Grid) QueryRadius(x, y, radius int) View {
+	area := Rect{X: x - radius, Y: y - radius, Width: 2*radius + 1, Height: 2*radius + 1}
+	view := View{Area: area, Pokemon: make([]VisiblePokemon, 0), Players: make([]VisiblePlayer, 0)}
+	if radius < 0 {
+		return view
+	}
+
+	within := func(cx, cy int) bool {
+		dx, dy := g.delta(x, cx, g.width), g.delta(y, cy, g.height)
+		return dx*dx+dy*dy <= radius*radius
+	}
+	for _, ys := range g.spans(area.Y, area.Height, g.height) {
+		for _, xs := range g.spans(area.X, area.Width, g.width) {
+			g.collect(xs, ys, within, &view)
+		}
+	}
+	sortView(&view)
+	return view
+}
+
+// spans - Tách đoạn [start, start+length) thành các đoạn nằm trong [0, size).
+// Có wrap thì phần vượt mép chuyển sang mép đối diện, không thì bị cắt bỏ.
+func (g *Grid) spans(start, length, size int) []span {
+	if length <= 0 {
+		return nil
+	}
+	if !g.wrap {
+		from, to := max(start, 0), min(start+length, size)
+		if from >= to {
+			return nil
+		}
+		return []span{{from, to}}
+	}
+
+	if length >= size {
+		return []span{{0, size}}
+	}
+	from := ((start % size) + size) % size
+	if from+length <= size {
+		return []span{{from, from + length}}
+	}
+	return []span{{from, size}, {0, from + length - size}}
+}
+
+// delta - Độ lệch ngắn nhất từ a tới b trên một trục (có dấu), có tính wrap-around
+func (g *Grid) delta(a, b, size int) int {
+	d := b - a
+	if g.wrap {
+		if d > size/2 {
+			d -= size
+		} else if d < -size/2 {
+			d += size
+		}
+	}
+	return d
+}
+
+// collect - Thêm vào view nội dung các ô trong vùng xs x ys thỏa within
+func (g *Grid) collect(xs, ys span, within func(x, y int) bool, view *View) {
+	for cy := ys.from / constants.WorldChunkSize; cy*constants.WorldChunkSize < ys.to; cy++ {
+		for cx := xs.from / constants.WorldChunkSize; cx*constants.WorldChunkSize < xs.to; cx++ {
+			c, exists := g.pinChunk(g.chunkIndex(cx, cy))
+			if !exists {
+				continue
+			}
+
+			c.mu.Lock()
+			cells := make(map[int]*Cell, len(c.cells))
+			for offset, cell := range c.cells {
+				cells[offset] = cell
+			}
+			c.mu.Unlock()
+
+			for offset, cell := range cells {
+				x := cx*constants.WorldChunkSize + offset%constants.WorldChunkSize
+				y := cy*constants.WorldChunkSize + offset/constants.WorldChunkSize
+				if x < xs.from || x >= xs.to || y < ys.from || y >= ys.to || !within(x, y) {
+					continue
+				}
+				cell.mu.RLock()
+				for _, pokemon := range cell.Pokemon {
+					view.Pokemon = append(view.Pokemon, VisiblePokemon{X: x, Y: y, Pokemon: pokemon})
+				}
+				for id := range cell.Players {
+					view.Players = append(view.Players, VisiblePlayer{X: x, Y: y, PlayerID: id})
+				}
+				cell.mu.RUnlock()
+			}
+			c.release()
+		}
+	}
+}
+
+func sortView(view *View) {
+	sort.Slice(view.Pokemon, func(i, j int) bool {
+		a, b := view.Pokemon[i], view.Pokemon[j]
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		if a.X != b.X {
+			return a.X < b.X
+		}
+		return a.Pokemon.Number < b.Pokemon.Number
+	})
+	sort.Slice(view.Players, func(i, j int) bool {
+		a, b := view.Players[i], view.Players[j]
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		if a.X != b.X {
+			return a.X < b.X
+		}
+		return a.PlayerID < b.PlayerID
+	})
+}
@@ -0,0 +1,194 @@
+package pokecat
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/game/pokebat"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// StartAutoMode - Cho player tự động di chuyển mỗi giây một ô trong duration theo chiến lược strategy
+func (g *Grid) StartAutoMode(player *models.Player, duration time.Duration, strategy string) error {
+	pos := player.GetPosition()
+	cell, release, err := g.pinCell(pos.X, pos.Y, false)
+	if err != nil {
+		return err
+	}
+	inWorld := false
+	if cell != nil {
+		cell.mu.RLock()
+		_, inWorld = cell.Players[player.GetID()]
+		cell.mu.RUnlock()
+	}
+	release()
+	if !inWorld {
+		return fmt.Errorf("player %s is not in the world", player.GetID())
+	}
+	if player.IsInBattle() {
+		return fmt.Errorf(constants.ErrBattleInProgress)
+	}
+
+	if err := player.StartAutoMode(duration, strategy); err != nil {
+		return err
+	}
+	g.mu.Lock()
+	g.autoMode[player.GetID()] = player
+	g.mu.Unlock()
+	return nil
+}
+
+// StopAutoMode - Dừng chế độ tự động di chuyển của player
+// (ví dụ khi nhận lời thách đấu, xem StopAutoModeOnChallenge)
+func (g *Grid) StopAutoMode(playerID string) {
+	g.mu.Lock()
+	player, exists := g.autoMode[playerID]
+	delete(g.autoMode, playerID)
+	g.mu.Unlock()
+
+	if exists {
+		player.StopAutoMode()
+	}
+}
+
+// StopAutoModeOnChallenge - Dừng chế độ tự động của cả hai player khi có lời thách đấu
+func (g *Grid) StopAutoModeOnChallenge(challenge pokebat.Challenge) {
+	g.StopAutoMode(challenge.ChallengerID)
+	g.StopAutoMode(challenge.OpponentID)
+}
+
+func (g *Grid) startAutoModeRoutine() {
+	g.autoTick = g.clock.NewTicker(time.Second / constants.MovementSpeed)
+
+	go func() {
+		for {
+			select {
+			case <-g.autoTick.C():
+				g.autoModeStep()
+			case <-g.done:
+				g.autoTick.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// autoModeStep - Mỗi player đang tự động di chuyển đi một bước, dừng khi hết giờ,
+// inventory đầy hoặc player vào battle
+func (g *Grid) autoModeStep() {
+	g.mu.RLock()
+	players := make([]*models.Player, 0, len(g.autoMode))
+	for _, player := range g.autoMode {
+		players = append(players, player)
+	}
+	g.mu.RUnlock()
+
+	now := g.clock.Now()
+	for _, player := range players {
+		auto := player.GetAutoMode()
+		if !auto.IsActive(now) || player.IsInventoryFull() || player.IsInBattle() {
+			g.StopAutoMode(player.GetID())
+			continue
+		}
+
+		// Bước bị chặn ở mép world vẫn được đếm để chiến lược quét chuyển hướng
+		result, err := g.move(player, g.autoDirection(player.GetPosition(), auto), false)
+		player.CountAutoStep()
+		if err != nil {
+			continue
+		}
+		if result.Encounter != nil || player.IsInventoryFull() {
+			g.StopAutoMode(player.GetID())
+		}
+	}
+}
+
+// autoDirection - Hướng đi kế tiếp theo chiến lược. Hướng bị địa hình chặn thì đổi sang
+// một hướng đi được khác; chiến lược quét vẫn đếm bước đó nên tiếp tục đúng nhịp zigzag.
+func (g *Grid) autoDirection(pos models.Position, auto models.AutoMode) constants.Direction {
+	var direction constants.Direction
+	switch auto.Strategy {
+	case constants.AutoStrategySweep:
+		direction = sweepDirection(auto.Steps)
+	case constants.AutoStrategyNearest:
+		var found bool
+		if direction, found = g.towardNearestSpawn(pos); !found {
+			direction = constants.Direction(g.rng.Intn(4))
+		}
+	default:
+		direction = constants.Direction(g.rng.Intn(4))
+	}
+
+	if !g.blockedByTerrain(pos, direction) {
+		return direction
+	}
+	open := make([]constants.Direction, 0, 4)
+	for candidate := constants.DirectionUp; candidate <= constants.DirectionRight; candidate++ {
+		if !g.blockedByTerrain(pos, candidate) {
+			open = append(open, candidate)
+		}
+	}
+	if len(open) == 0 {
+		return direction
+	}
+	return open[g.rng.Intn(len(open))]
+}
+
+// blockedByTerrain - Bước từ pos theo hướng direction đi vào địa hình không đi được
+func (g *Grid) blockedByTerrain(pos models.Position, direction constants.Direction) bool {
+	_, err := g.step(pos, direction)
+	return err != nil && err.Error() == constants.ErrImpassableTerrain
+}
+
+// sweepDirection - Quét zigzag: đi ngang AutoSweepWidth ô, xuống một hàng rồi đi ngược lại
+func sweepDirection(steps int) constants.Direction {
+	segment := constants.AutoSweepWidth + 1
+	if steps%segment == constants.AutoSweepWidth {
+		return constants.DirectionDown
+	}
+	if (steps/segment)%2 == 0 {
+		return constants.DirectionRight
+	}
+	return constants.DirectionLeft
+}
+
+// towardNearestSpawn - Hướng về Pokemon gần nhất (khoảng cách Manhattan) trong vùng
+// cách player AutoVisionRadius ô
+func (g *Grid) towardNearestSpawn(pos models.Position) (constants.Direction, bool) {
+	bestDX, bestDY, bestDistance := 0, 0, -1
+	radius := constants.AutoVisionRadius
+	view := g.QueryRect(Rect{X: pos.X - radius, Y: pos.Y - radius, Width: 2*radius + 1, Height: 2*radius + 1})
+	for _, visible := range view.Pokemon {
+		dx, dy := g.delta(pos.X, visible.X, g.width), g.delta(pos.Y, visible.Y, g.height)
+		distance := abs(dx) + abs(dy)
+		if distance == 0 {
+			continue
+		}
+		// Cùng khoảng cách thì ưu tiên hàng trên rồi cột trái
+		if bestDistance < 0 || distance < bestDistance ||
+			(distance == bestDistance && (dy < bestDY || (dy == bestDY && dx < bestDX))) {
+			bestDX, bestDY, bestDistance = dx, dy, distance
+		}
+	}
+
+	switch {
+	case bestDistance < 0:
+		return 0, false
+	case bestDX > 0:
+		return constants.DirectionRight, true
+	case bestDX < 0:
+		return constants.DirectionLeft, true
+	case bestDY > 0:
+		return constants.DirectionDown, true
+	default:
+		return constants.DirectionUp, true
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,65 @@
+package pokecat
+
+import (
+	"sort"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// CaptureEvent - Player tự động bắt được Pokemon khi bước vào ô
+type CaptureEvent struct {
+	PlayerID string
+	X        int
+	Y        int
+	Pokemon  *models.Pokemon
+	Time     time.Time
+}
+
+// captureAll - Bắt mọi Pokemon trong ô cho player, gọi khi đang giữ lock của ô.
+// Inventory chỉ được cập nhật trong bộ nhớ, người gọi lưu file sau khi thả lock.
+// Inventory đầy thì dừng lại và để Pokemon ở chỗ cũ.
+func (g *Grid) captureAll(player *models.Player, x, y int, cell *Cell) []CaptureEvent {
+	numbers := make([]string, 0, len(cell.Pokemon))
+	for number := range cell.Pokemon {
+		numbers = append(numbers, number)
+	}
+	sort.Strings(numbers)
+
+	var captures []CaptureEvent
+	for _, number := range numbers {
+		pokemon := cell.Pokemon[number]
+		if err := player.CollectPokemon(pokemon); err != nil {
+			break
+		}
+		delete(cell.Pokemon, number)
+		captures = append(captures, CaptureEvent{
+			PlayerID: player.GetID(),
+			X:        x,
+			Y:        y,
+			Pokemon:  pokemon,
+			Time:     g.clock.Now(),
+		})
+	}
+	return captures
+}
+
+// notifyCaptures - Báo các lần bắt Pokemon qua event world và hook OnCapture
+func (g *Grid) notifyCaptures(captures []CaptureEvent) {
+	for _, capture := range captures {
+		g.publishCapture(capture.PlayerID, capture.X, capture.Y, capture.Pokemon)
+	}
+	if g.OnCapture == nil {
+		return
+	}
+	for _, capture := range captures {
+		go g.OnCapture(capture)
+	}
+}
+
+// publishCapture - Báo player đã bắt Pokemon tại (x, y) cho subscriber
+func (g *Grid) publishCapture(playerID string, x, y int, pokemon *models.Pokemon) {
+	event := pokemonEvent(WorldEventCapture, x, y, pokemon)
+	event.PlayerID = playerID
+	g.publish(event)
+}
@@ -0,0 +1,94 @@
+package pokecat
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// TestSimultaneousEntryCapturesOnce - Nhiều player cùng bước vào một ô có Pokemon thì chỉ một người bắt được.
+// Chạy với -race.
+func TestSimultaneousEntryCapturesOnce(t *testing.T) {
+	const rounds = 20
+	neighbours := []struct {
+		x, y      int
+		direction constants.Direction
+	}{
+		{9, 10, constants.DirectionRight},
+		{11, 10, constants.DirectionLeft},
+		{10, 9, constants.DirectionDown},
+		{10, 11, constants.DirectionUp},
+	}
+
+	for round := 0; round < rounds; round++ {
+		grid, clk := newTestGrid(t, testConfig(20, 20), int64(round))
+		pikachu := newTestPokemon(t, "Pikachu")
+		grid.placePokemon(10, 10, pikachu)
+
+		hooked := make(chan CaptureEvent, 2*len(neighbours))
+		grid.OnCapture = func(capture CaptureEvent) { hooked <- capture }
+
+		// Hai player ở mỗi ô bên cạnh
+		players := make([]*models.Player, 0, 2*len(neighbours))
+		directions := make([]constants.Direction, 0, cap(players))
+		for i, from := range neighbours {
+			for j := 0; j < 2; j++ {
+				id := fmt.Sprintf("entrant-%d-%d-%d", round, i, j)
+				players = append(players, newTestPlayer(t, grid, clk, id, from.x, from.y))
+				directions = append(directions, from.direction)
+			}
+		}
+
+		start := make(chan struct{})
+		captures := make([]int, len(players))
+		var wg sync.WaitGroup
+		for i, player := range players {
+			wg.Add(1)
+			go func(i int, player *models.Player) {
+				defer wg.Done()
+				<-start
+				result, err := grid.move(player, directions[i], false)
+				if err != nil {
+					t.Errorf("move %s: %v", player.GetID(), err)
+					return
+				}
+				captures[i] = len(result.Captures)
+			}(i, player)
+		}
+		close(start)
+		wg.Wait()
+
+		owners := 0
+		total := 0
+		for i, player := range players {
+			total += captures[i]
+			if _, err := player.GetPokemon(pikachu.Number); err == nil {
+				owners++
+			}
+		}
+		if total != 1 || owners != 1 {
+			t.Fatalf("round %d: %d captures reported and %d inventories hold the pokemon, want 1 and 1",
+				round, total, owners)
+		}
+		select {
+		case capture := <-hooked:
+			if capture.Pokemon.Number != pikachu.Number {
+				t.Fatalf("round %d: capture event for %s, want %s", round, capture.Pokemon.Name, pikachu.Name)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("round %d: no capture event", round)
+		}
+		select {
+		case capture := <-hooked:
+			t.Fatalf("round %d: second capture event for %s", round, capture.PlayerID)
+		case <-time.After(10 * time.Millisecond):
+		}
+		if left := grid.GetNearbyPokemons(10, 10); len(left) != 0 {
+			t.Fatalf("round %d: pokemon still in the cell after capture: %v", round, left)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package pokecat
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// chunk - Vùng WorldChunkSize x WorldChunkSize ô, chỉ ô đã được dùng mới được tạo
+type chunk struct {
+	cells map[int]*Cell // Vị trí trong chunk (y*WorldChunkSize + x) -> ô
+	mu    sync.Mutex
+	pins  int32 // Số thao tác đang giữ con trỏ tới ô của chunk, chunk được ghim thì không bị thu hồi
+}
+
+func newCell() *Cell {
+	return &Cell{
+		Players: make(map[string]*models.Player),
+		Pokemon: make(map[string]*models.Pokemon),
+	}
+}
+
+func (c *Cell) isEmpty() bool {
+	return len(c.Players) == 0 && len(c.Pokemon) == 0
+}
+
+// chunkKey - Khóa của chunk chứa ô (x, y) và vị trí của ô trong chunk
+func (g *Grid) chunkKey(x, y int) (int, int) {
+	key := g.chunkIndex(x/constants.WorldChunkSize, y/constants.WorldChunkSize)
+	offset := (y%constants.WorldChunkSize)*constants.WorldChunkSize + x%constants.WorldChunkSize
+	return key, offset
+}
+
+// chunkIndex - Khóa của chunk ở cột cx, hàng cy
+func (g *Grid) chunkIndex(cx, cy int) int {
+	chunksPerRow := (g.width + constants.WorldChunkSize - 1) / constants.WorldChunkSize
+	return cy*chunksPerRow + cx
+}
+
+// pinChunk - Ghim chunk đã tồn tại; trả về false nếu chunk chưa được tạo
+func (g *Grid) pinChunk(key int) (*chunk, bool) {
+	g.chunkMu.RLock()
+	defer g.chunkMu.RUnlock()
+
+	c, exists := g.chunks[key]
+	if exists {
+		atomic.AddInt32(&c.pins, 1)
+	}
+	return c, exists
+}
+
+func (c *chunk) release() {
+	atomic.AddInt32(&c.pins, -1)
+}
+
+// pinCell - Lấy ô tại (x, y) và ghim chunk chứa nó cho tới khi gọi release.
+// create=false thì trả về nil nếu ô chưa được tạo (ô trống), tránh cấp phát khi chỉ đọc.
+func (g *Grid) pinCell(x, y int, create bool) (*Cell, func(), error) {
+	if !g.isValidPosition(x, y) {
+		return nil, nil, errInvalidPosition(x, y)
+	}
+	key, offset := g.chunkKey(x, y)
+
+	c, exists := g.pinChunk(key)
+	if !exists {
+		if !create {
+			return nil, func() {}, nil
+		}
+		g.chunkMu.Lock()
+		if c, exists = g.chunks[key]; !exists {
+			c = &chunk{cells: make(map[int]*Cell)}
+			g.chunks[key] = c
+		}
+		atomic.AddInt32(&c.pins, 1)
+		g.chunkMu.Unlock()
+	}
+	release := c.release
+
+	c.mu.Lock()
+	cell, exists := c.cells[offset]
+	if !exists && create {
+		cell = newCell()
+		c.cells[offset] = cell
+	}
+	c.mu.Unlock()
+	return cell, release, nil
+}
+
+func (g *Grid) startReclaimRoutine() {
+	g.reclaimTick = g.clock.NewTicker(time.Duration(constants.ChunkReclaimInterval) * time.Second)
+
+	go func() {
+		for {
+			select {
+			case <-g.reclaimTick.C():
+				g.reclaimChunks()
+			case <-g.done:
+				g.reclaimTick.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// reclaimChunks - Xóa ô trống và chunk không còn ô nào. Chunk đang bị ghim được bỏ qua;
+// giữ lock ghi của chunkMu nên không ai ghim thêm được trong lúc thu hồi.
+func (g *Grid) reclaimChunks() {
+	g.chunkMu.Lock()
+	defer g.chunkMu.Unlock()
+
+	for key, c := range g.chunks {
+		if atomic.LoadInt32(&c.pins) > 0 {
+			continue
+		}
+		for offset, cell := range c.cells {
+			if cell.isEmpty() {
+				delete(c.cells, offset)
+			}
+		}
+		if len(c.cells) == 0 {
+			delete(g.chunks, key)
+		}
+	}
+}
+
+// LoadedChunks - Số chunk đang được cấp phát
+func (g *Grid) LoadedChunks() int {
+	g.chunkMu.RLock()
+	defer g.chunkMu.RUnlock()
+	return len(g.chunks)
+}
@@ -0,0 +1,195 @@
+package pokecat
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/clock"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/random"
+)
+
+func TestPinCellKeepsChunkUntilRelease(t *testing.T) {
+	grid, _ := newTestGrid(t, testConfig(100, 100), 1)
+
+	cell, release, err := grid.PinCell(40, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid.reclaimChunks()
+	if grid.LoadedChunks() != 1 {
+		t.Fatalf("pinned chunk was reclaimed")
+	}
+	if again, releaseAgain, _ := grid.PinCell(40, 40); again != cell {
+		t.Errorf("PinCell returned a different cell while pinned")
+	} else {
+		releaseAgain()
+	}
+
+	release()
+	grid.reclaimChunks()
+	if grid.LoadedChunks() != 0 {
+		t.Errorf("%d chunks loaded after release, want 0", grid.LoadedChunks())
+	}
+	if _, _, err := grid.PinCell(100, 0); err == nil {
+		t.Errorf("PinCell outside the world did not fail")
+	}
+}
+
+func TestGetCellReturnsUnpinnedSnapshot(t *testing.T) {
+	grid, clk := newTestGrid(t, testConfig(100, 100), 1)
+
+	empty, err := grid.GetCell(40, 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(empty.Players) != 0 || len(empty.Pokemon) != 0 {
+		t.Errorf("unloaded cell is not empty: %+v", empty)
+	}
+	if grid.LoadedChunks() != 0 {
+		t.Errorf("GetCell loaded %d chunks for an empty cell", grid.LoadedChunks())
+	}
+
+	newTestPlayer(t, grid, clk, "snapshot", 5, 5)
+	cell, err := grid.GetCell(5, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found := cell.Players["snapshot"]; !found {
+		t.Fatal("snapshot is missing the player in the cell")
+	}
+	// Sửa bản sao không ảnh hưởng tới world
+	delete(cell.Players, "snapshot")
+	if again, _ := grid.GetCell(5, 5); len(again.Players) != 1 {
+		t.Errorf("changing the snapshot removed the player from the world")
+	}
+	if _, err := grid.GetCell(100, 0); err == nil {
+		t.Errorf("GetCell outside the world did not fail")
+	}
+}
+
+func TestReclaimKeepsOccupiedCells(t *testing.T) {
+	grid, clk := newTestGrid(t, testConfig(100, 100), 1)
+	newTestPlayer(t, grid, clk, "occupant", 5, 5)
+	grid.placePokemon(70, 70, newTestPokemon(t, "Pikachu"))
+
+	grid.reclaimChunks()
+	if grid.LoadedChunks() != 2 {
+		t.Errorf("%d chunks loaded, want 2 (player and pokemon)", grid.LoadedChunks())
+	}
+}
+
+// benchmarkSizes - World 1000x1000 (mặc định) và 10000x10000 (MaxWorldSize)
+var benchmarkSizes = []int{1000, constants.MaxWorldSize}
+
+func BenchmarkPinCell(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			grid, _ := newTestGrid(b, testConfig(size, size), 1)
+			rng := rand.New(rand.NewSource(1))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, release, err := grid.pinCell(rng.Intn(size), rng.Intn(size), true)
+				if err != nil {
+					b.Fatal(err)
+				}
+				release()
+			}
+		})
+	}
+}
+
+// BenchmarkGridMemory - Bộ nhớ heap của grid mới tạo sau một đợt spawn, không tính phần của test
+func BenchmarkGridMemory(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			cfg := testConfig(size, size)
+			var total int64
+			for i := 0; i < b.N; i++ {
+				var before, after runtime.MemStats
+				runtime.GC()
+				runtime.ReadMemStats(&before)
+
+				grid := NewGridWithSources(cfg, clock.NewFake(testStart), random.New(1))
+				grid.spawnPokemonWave()
+
+				runtime.GC()
+				runtime.ReadMemStats(&after)
+				total += int64(after.HeapAlloc) - int64(before.HeapAlloc)
+				grid.Cleanup()
+			}
+			b.ReportMetric(float64(total)/float64(b.N), "heap-bytes")
+		})
+	}
+}
+
+func BenchmarkMove(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			cfg := testConfig(size, size)
+			grid, clk := newTestGrid(b, cfg, 1)
+			player := newTestPlayer(b, grid, clk, "bench-mover", size/2, size/2)
+			directions := []constants.Direction{
+				constants.DirectionRight, constants.DirectionDown, constants.DirectionLeft, constants.DirectionUp,
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// Đi qua biên chunk đều đặn: 64 bước mỗi hướng
+				if _, err := grid.move(player, directions[(i/64)%len(directions)], false); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSpawnWave(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			grid, _ := newTestGrid(b, testConfig(size, size), 1)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				grid.spawnPokemonWave()
+			}
+		})
+	}
+}
+
+func BenchmarkQueryRadius(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			grid, _ := newTestGrid(b, testConfig(size, size), 1)
+			// Mật độ Pokemon tương đương một world đã chạy vài đợt spawn
+			for i := 0; i < 20; i++ {
+				grid.spawnPokemonWave()
+			}
+			rng := rand.New(rand.NewSource(1))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				grid.QueryRadius(rng.Intn(size), rng.Intn(size), constants.DefaultViewRadius)
+			}
+		})
+	}
+}
+
+func BenchmarkReclaimChunks(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%dx%d", size, size), func(b *testing.B) {
+			grid, _ := newTestGrid(b, testConfig(size, size), 1)
+			for i := 0; i < 20; i++ {
+				grid.spawnPokemonWave()
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				grid.reclaimChunks()
+			}
+		})
+	}
+}
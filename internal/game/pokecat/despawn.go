@@ -0,0 +1,132 @@
+package pokecat
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// despawnEntry - Một lần spawn chờ hết hạn. Pokemon giữ đúng con trỏ đã đặt vào ô
+// để không xóa nhầm Pokemon cùng number spawn sau đó.
+type despawnEntry struct {
+	id      uint64 // Thứ tự spawn, phá hòa khi hết hạn cùng lúc
+	at      time.Time
+	x       int
+	y       int
+	pokemon *models.Pokemon
+}
+
+// despawnQueue - Min-heap theo thời điểm hết hạn
+type despawnQueue []*despawnEntry
+
+func (q despawnQueue) Len() int { return len(q) }
+
+func (q despawnQueue) Less(i, j int) bool {
+	if q[i].at.Equal(q[j].at) {
+		return q[i].id < q[j].id
+	}
+	return q[i].at.Before(q[j].at)
+}
+
+func (q despawnQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *despawnQueue) Push(x any) { *q = append(*q, x.(*despawnEntry)) }
+
+func (q *despawnQueue) Pop() any {
+	old := *q
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return entry
+}
+
+// despawnScheduler - Hàng đợi despawn dùng chung cho cả world thay cho một goroutine mỗi Pokemon
+type despawnScheduler struct {
+	queue  despawnQueue
+	nextID uint64
+	mu     sync.Mutex
+}
+
+// schedule - Hẹn despawn Pokemon tại (x, y) vào thời điểm at
+func (s *despawnScheduler) schedule(x, y int, pokemon *models.Pokemon, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	heap.Push(&s.queue, &despawnEntry{id: s.nextID, at: at, x: x, y: y, pokemon: pokemon})
+}
+
+// due - Lấy ra các lần spawn đã hết hạn tính tới now
+func (s *despawnScheduler) due(now time.Time) []*despawnEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expired []*despawnEntry
+	for len(s.queue) > 0 && !s.queue[0].at.After(now) {
+		expired = append(expired, heap.Pop(&s.queue).(*despawnEntry))
+	}
+	return expired
+}
+
+// pending - Số lần spawn còn chờ despawn
+func (s *despawnScheduler) pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queue)
+}
+
+// scheduleDespawn - Hẹn despawn Pokemon vừa spawn sau DespawnTime
+func (g *Grid) scheduleDespawn(x, y int, pokemon *models.Pokemon) {
+	at := g.clock.Now().Add(time.Duration(g.spawn.DespawnTime) * time.Second)
+	g.despawns.schedule(x, y, pokemon, at)
+}
+
+func (g *Grid) startDespawnRoutine() {
+	ticker := g.clock.NewTicker(time.Duration(constants.DespawnCheckInterval) * time.Second)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				g.despawnExpired()
+			case <-g.done:
+				return
+			}
+		}
+	}()
+}
+
+// despawnExpired - Xóa các Pokemon đã hết hạn còn nằm trong ô.
+// Pokemon đã bị bắt, hoặc bị thay bởi lần spawn khác cùng number, thì bỏ qua.
+// Pokemon đang trong battle hoang dã được gia hạn EncounterDespawnGrace để battle kết thúc trước.
+func (g *Grid) despawnExpired() {
+	now := g.clock.Now()
+	for _, entry := range g.despawns.due(now) {
+		if g.encounterLocked(entry.x, entry.y, entry.pokemon.Number) {
+			g.despawns.schedule(entry.x, entry.y, entry.pokemon,
+				now.Add(time.Duration(constants.EncounterDespawnGrace)*time.Second))
+			continue
+		}
+
+		cell, release, err := g.pinCell(entry.x, entry.y, false)
+		if err != nil {
+			continue
+		}
+		if cell != nil {
+			cell.mu.Lock()
+			expired := cell.Pokemon[entry.pokemon.Number] == entry.pokemon
+			if expired {
+				delete(cell.Pokemon, entry.pokemon.Number)
+			}
+			cell.mu.Unlock()
+			if expired {
+				g.publish(pokemonEvent(WorldEventDespawn, entry.x, entry.y, entry.pokemon))
+			}
+		}
+		release()
+	}
+}
@@ -0,0 +1,129 @@
+package pokecat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+func TestDespawnSchedulerOrder(t *testing.T) {
+	var s despawnScheduler
+	pokemon := &models.Pokemon{Number: "0025"}
+	// Thứ tự hẹn: id 1..5; id 2 và 4 cùng hết hạn, id 5 hết hạn sớm nhất
+	for _, offset := range []int{30, 20, 40, 20, 10} {
+		s.schedule(offset, 0, pokemon, testStart.Add(time.Duration(offset)*time.Second))
+	}
+
+	if due := s.due(testStart.Add(9 * time.Second)); len(due) != 0 {
+		t.Fatalf("%d entries due before the first deadline", len(due))
+	}
+
+	due := s.due(testStart.Add(30 * time.Second))
+	wantIDs := []uint64{5, 2, 4, 1}
+	if len(due) != len(wantIDs) {
+		t.Fatalf("%d entries due at +30s, want %d", len(due), len(wantIDs))
+	}
+	for i, entry := range due {
+		if entry.id != wantIDs[i] {
+			t.Errorf("due[%d] has id %d, want %d", i, entry.id, wantIDs[i])
+		}
+		if i > 0 && entry.at.Before(due[i-1].at) {
+			t.Errorf("due[%d] expires at %v, before the previous entry", i, entry.at)
+		}
+	}
+	if s.pending() != 1 {
+		t.Errorf("%d entries pending, want 1", s.pending())
+	}
+}
+
+// newDespawnGrid - Grid với DespawnTime 10 giây; advance tiến đồng hồ rồi xử lý despawn ngay
+func newDespawnGrid(t *testing.T) (*Grid, func(d time.Duration)) {
+	t.Helper()
+	cfg := testConfig(50, 50)
+	cfg.World.DespawnTime = 10
+	grid, clk := newTestGrid(t, cfg, 1)
+	advance := func(d time.Duration) {
+		clk.Advance(d)
+		grid.despawnExpired()
+	}
+	return grid, advance
+}
+
+func spawnAt(grid *Grid, x, y int, pokemon *models.Pokemon) {
+	grid.placePokemon(x, y, pokemon)
+	grid.scheduleDespawn(x, y, pokemon)
+}
+
+func TestDespawnAtScheduledTime(t *testing.T) {
+	grid, advance := newDespawnGrid(t)
+	pikachu := newTestPokemon(t, "Pikachu")
+	spawnAt(grid, 3, 4, pikachu)
+
+	advance(10*time.Second - time.Nanosecond)
+	if _, exists := grid.GetNearbyPokemons(3, 4)[pikachu.Number]; !exists {
+		t.Fatalf("pokemon despawned before its deadline")
+	}
+	advance(time.Nanosecond)
+	if _, exists := grid.GetNearbyPokemons(3, 4)[pikachu.Number]; exists {
+		t.Fatalf("pokemon still present at its deadline")
+	}
+	if grid.despawns.pending() != 0 {
+		t.Errorf("%d despawns pending, want 0", grid.despawns.pending())
+	}
+}
+
+func TestDespawnSkipsReplacedSpawn(t *testing.T) {
+	grid, advance := newDespawnGrid(t)
+	first := newTestPokemon(t, "Pikachu")
+	spawnAt(grid, 3, 4, first)
+
+	// Bị bắt rồi spawn lại cùng number vào cùng ô: lần hẹn cũ không được xóa Pokemon mới
+	advance(5 * time.Second)
+	if _, err := grid.CatchPokemon(3, 4, first.Number); err != nil {
+		t.Fatal(err)
+	}
+	second := newTestPokemon(t, "Pikachu")
+	spawnAt(grid, 3, 4, second)
+
+	advance(5 * time.Second)
+	if got := grid.GetNearbyPokemons(3, 4)[second.Number]; got != second {
+		t.Fatalf("respawned pokemon removed by the first spawn's expiry")
+	}
+	advance(5 * time.Second)
+	if _, exists := grid.GetNearbyPokemons(3, 4)[second.Number]; exists {
+		t.Errorf("respawned pokemon still present after its own deadline")
+	}
+}
+
+func TestDespawnRearmsLockedSpawn(t *testing.T) {
+	grid, advance := newDespawnGrid(t)
+	pikachu := newTestPokemon(t, "Pikachu")
+	spawnAt(grid, 3, 4, pikachu)
+
+	key := spawnKey(3, 4, pikachu.Number)
+	grid.encounterMu.Lock()
+	grid.encounters[key] = &Encounter{PlayerID: "trainer", X: 3, Y: 4, PokemonNumber: pikachu.Number}
+	grid.encounterMu.Unlock()
+
+	advance(10 * time.Second)
+	if _, exists := grid.GetNearbyPokemons(3, 4)[pikachu.Number]; !exists {
+		t.Fatalf("pokemon in an encounter was despawned")
+	}
+	if grid.despawns.pending() != 1 {
+		t.Fatalf("%d despawns pending, want the re-armed entry", grid.despawns.pending())
+	}
+
+	// Battle kết thúc: lần gia hạn tiếp theo xóa Pokemon
+	grid.releaseEncounter(key)
+	grace := time.Duration(constants.EncounterDespawnGrace) * time.Second
+	advance(grace - time.Second)
+	if _, exists := grid.GetNearbyPokemons(3, 4)[pikachu.Number]; !exists {
+		t.Fatalf("pokemon despawned before the grace period ended")
+	}
+	advance(time.Second)
+	if _, exists := grid.GetNearbyPokemons(3, 4)[pikachu.Number]; exists {
+		t.Errorf("pokemon still present after the grace period")
+	}
+}
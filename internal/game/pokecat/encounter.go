@@ -0,0 +1,159 @@
+package pokecat
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/game/pokebat"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// Encounter - Battle đang diễn ra với một Pokemon hoang dã, giữ khóa trên Pokemon đó
+type Encounter struct {
+	PlayerID      string
+	X             int
+	Y             int
+	PokemonNumber string
+	Battle        *pokebat.Battle
+}
+
+// EnableEncounters - Bật chế độ gặp Pokemon hoang dã: bước vào ô có Pokemon sẽ bắt đầu battle
+// theo thể thức formatName với team của player, battle được quản lý bởi manager
+func (g *Grid) EnableEncounters(manager *pokebat.Manager, formatName string) error {
+	format, err := pokebat.GetFormat(formatName)
+	if err != nil {
+		return err
+	}
+
+	g.encounterMu.Lock()
+	defer g.encounterMu.Unlock()
+	g.battles = manager
+	g.battleRules = format
+	return nil
+}
+
+func (g *Grid) encountersEnabled() bool {
+	g.encounterMu.Lock()
+	defer g.encounterMu.Unlock()
+	return g.battles != nil
+}
+
+// StartEncounter - Khóa Pokemon hoang dã và bắt đầu battle với team của player.
+// Thắng hoặc bắt được sẽ xóa Pokemon khỏi grid; thua hoặc hết giờ thì Pokemon được thả khóa.
+func (g *Grid) StartEncounter(player *models.Player, x, y int, pokemonNumber string) (*Encounter, error) {
+	if !g.isValidPosition(x, y) {
+		return nil, errInvalidPosition(x, y)
+	}
+
+	key := spawnKey(x, y, pokemonNumber)
+	g.encounterMu.Lock()
+	manager, format := g.battles, g.battleRules
+	if manager == nil {
+		g.encounterMu.Unlock()
+		return nil, fmt.Errorf("encounter mode is disabled")
+	}
+	if _, locked := g.encounters[key]; locked {
+		g.encounterMu.Unlock()
+		return nil, fmt.Errorf(constants.ErrPokemonLocked)
+	}
+	pokemon, exists := g.GetNearbyPokemons(x, y)[pokemonNumber]
+	if !exists {
+		g.encounterMu.Unlock()
+		return nil, fmt.Errorf("pokemon not found at position (%d,%d)", x, y)
+	}
+
+	// Giữ chỗ trước khi tạo battle để player khác không thể gặp cùng Pokemon
+	encounter := &Encounter{PlayerID: player.GetID(), X: x, Y: y, PokemonNumber: pokemonNumber}
+	g.encounters[key] = encounter
+	g.encounterMu.Unlock()
+
+	battle, err := manager.StartWildBattle(player, pokemon, format)
+	if err != nil {
+		g.releaseEncounter(key)
+		return nil, err
+	}
+	encounter.Battle = battle
+
+	events, unsubscribe := battle.Subscribe(constants.EventBufferSize)
+	go func() {
+		defer unsubscribe()
+		for range events {
+		}
+		g.resolveEncounter(key, encounter, player)
+	}()
+	return encounter, nil
+}
+
+// GetEncounter - Lấy battle hoang dã đang giữ khóa Pokemon
+func (g *Grid) GetEncounter(x, y int, pokemonNumber string) (*Encounter, bool) {
+	g.encounterMu.Lock()
+	defer g.encounterMu.Unlock()
+	encounter, exists := g.encounters[spawnKey(x, y, pokemonNumber)]
+	return encounter, exists
+}
+
+// encounterAt - Bắt đầu battle với Pokemon đầu tiên chưa bị khóa trong ô.
+// Player không thể vào battle (đang bận, chưa chọn team) thì bỏ qua và trả về nil.
+func (g *Grid) encounterAt(player *models.Player, x, y int) *Encounter {
+	numbers := make([]string, 0)
+	for number := range g.GetNearbyPokemons(x, y) {
+		numbers = append(numbers, number)
+	}
+	sort.Strings(numbers)
+
+	for _, number := range numbers {
+		if _, locked := g.GetEncounter(x, y, number); locked {
+			continue
+		}
+		if encounter, err := g.StartEncounter(player, x, y, number); err == nil {
+			return encounter
+		}
+	}
+	return nil
+}
+
+// resolveEncounter - Xử lý kết quả battle hoang dã đã kết thúc rồi thả khóa
+func (g *Grid) resolveEncounter(key string, encounter *Encounter, player *models.Player) {
+	defer g.releaseEncounter(key)
+
+	winnerID, reason := encounter.Battle.GetResult()
+	if winnerID != encounter.PlayerID {
+		return
+	}
+
+	pokemon, err := g.CatchPokemon(encounter.X, encounter.Y, encounter.PokemonNumber)
+	if err != nil {
+		// Đã despawn
+		return
+	}
+	if reason != pokebat.EndReasonCapture {
+		// Bị đánh bại và bỏ chạy
+		g.publish(pokemonEvent(WorldEventDespawn, encounter.X, encounter.Y, pokemon))
+		return
+	}
+	if err := player.AddPokemon(pokemon); err != nil {
+		// Không thêm được vào inventory - trả Pokemon về chỗ cũ
+		g.placePokemon(encounter.X, encounter.Y, pokemon)
+		return
+	}
+	g.publishCapture(player.GetID(), encounter.X, encounter.Y, pokemon)
+}
+
+// encounterLocked - Pokemon đang bị khóa bởi một battle hoang dã
+func (g *Grid) encounterLocked(x, y int, pokemonNumber string) bool {
+	g.encounterMu.Lock()
+	defer g.encounterMu.Unlock()
+	_, locked := g.encounters[spawnKey(x, y, pokemonNumber)]
+	return locked
+}
+
+func (g *Grid) releaseEncounter(key string) {
+	g.encounterMu.Lock()
+	delete(g.encounters, key)
+	g.encounterMu.Unlock()
+}
+
+func spawnKey(x, y int, pokemonNumber string) string {
+	return fmt.Sprintf("%d,%d,%s", x, y, pokemonNumber)
+}
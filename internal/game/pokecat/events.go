@@ -0,0 +1,346 @@
+package pokecat
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// WorldEventType - Loại event của world
+type WorldEventType string
+
+const (
+	WorldEventSpawn       WorldEventType = "spawn"        // Pokemon xuất hiện trong ô
+	WorldEventDespawn     WorldEventType = "despawn"      // Pokemon biến mất khỏi ô
+	WorldEventPlayerEnter WorldEventType = "player_enter" // Player vào world
+	WorldEventPlayerLeave WorldEventType = "player_leave" // Player rời world
+	WorldEventMove        WorldEventType = "move"         // Player đi từ (FromX, FromY) sang (X, Y)
+	WorldEventCapture     WorldEventType = "capture"      // Player bắt được Pokemon trong ô
+)
+
+// WorldEvent - Một thay đổi trong world tại ô (X, Y)
+type WorldEvent struct {
+	Seq           uint64         `json:"seq"`
+	Type          WorldEventType `json:"type"`
+	X             int            `json:"x"`
+	Y             int            `json:"y"`
+	FromX         int            `json:"from_x,omitempty"`
+	FromY         int            `json:"from_y,omitempty"`
+	PlayerID      string         `json:"player_id,omitempty"`
+	PokemonNumber string         `json:"pokemon_number,omitempty"`
+	PokemonName   string         `json:"pokemon_name,omitempty"`
+	PokemonLevel  int            `json:"pokemon_level,omitempty"`
+	Time          time.Time      `json:"time"`
+}
+
+// DropPolicy - Cách xử lý khi buffer của subscriber đã đầy
+type DropPolicy int
+
+const (
+	DropNewest DropPolicy = iota // Bỏ event mới (giống event battle)
+	DropOldest                   // Bỏ event cũ nhất trong buffer để nhận event mới
+	CloseOnLag                   // Đóng subscription, subscriber cần lấy lại view rồi đăng ký lại
+)
+
+// SubscribeOptions - Vùng quan tâm, kích thước buffer và chính sách khi subscriber chậm
+type SubscribeOptions struct {
+	Area   *Rect          // Vùng cố định; nil và không Follow thì nhận event của cả world
+	Follow *models.Player // Vùng vuông bán kính Radius quanh player, tự cập nhật khi player di chuyển
+	Radius int
+	Buffer int // 0 dùng WorldEventBufferSize
+	Policy DropPolicy
+}
+
+// Subscription - Đăng ký nhận event world trong vùng quan tâm
+type Subscription struct {
+	id       uint64
+	ch       chan WorldEvent
+	policy   DropPolicy
+	follow   string // ID player được theo, rỗng nếu vùng cố định
+	radius   int
+	area     *Rect // nil là cả world (cần giữ bus.mu)
+	chunks   []int // Chunk giao với vùng, dùng để tra cứu nhanh (cần giữ bus.mu)
+	dropped  uint64
+	lagged   atomic.Bool
+	closed   bool
+	mu       sync.Mutex
+	grid     *Grid
+	closeOne sync.Once
+}
+
+// eventBus - Danh sách subscriber, đánh chỉ mục theo chunk để mỗi event chỉ xét subscriber ở gần
+type eventBus struct {
+	subs      map[uint64]*Subscription
+	global    map[uint64]*Subscription            // Subscriber nhận event của cả world
+	byChunk   map[int]map[uint64]*Subscription    // chunk -> subscriber có vùng giao với chunk
+	followers map[string]map[uint64]*Subscription // playerID -> subscriber theo player đó
+	nextID    uint64
+	seq       uint64
+	mu        sync.RWMutex
+}
+
+func newEventBus() eventBus {
+	return eventBus{
+		subs:      make(map[uint64]*Subscription),
+		global:    make(map[uint64]*Subscription),
+		byChunk:   make(map[int]map[uint64]*Subscription),
+		followers: make(map[string]map[uint64]*Subscription),
+	}
+}
+
+// Subscribe - Đăng ký nhận event trong vùng quan tâm. Channel bị đóng khi gọi Close,
+// khi grid Cleanup, hoặc khi subscriber quá chậm với chính sách CloseOnLag.
+func (g *Grid) Subscribe(opts SubscribeOptions) *Subscription {
+	buffer := opts.Buffer
+	if buffer <= 0 {
+		buffer = constants.WorldEventBufferSize
+	}
+	sub := &Subscription{ch: make(chan WorldEvent, buffer), policy: opts.Policy, grid: g}
+
+	var area *Rect
+	switch {
+	case opts.Follow != nil:
+		sub.follow = opts.Follow.GetID()
+		sub.radius = opts.Radius
+		area = followArea(opts.Follow.GetPosition(), opts.Radius)
+	case opts.Area != nil:
+		rect := *opts.Area
+		area = &rect
+	}
+
+	g.bus.mu.Lock()
+	defer g.bus.mu.Unlock()
+
+	select {
+	case <-g.done:
+		close(sub.ch)
+		sub.closed = true
+		return sub
+	default:
+	}
+
+	g.bus.nextID++
+	sub.id = g.bus.nextID
+	g.bus.subs[sub.id] = sub
+	if sub.follow != "" {
+		if g.bus.followers[sub.follow] == nil {
+			g.bus.followers[sub.follow] = make(map[uint64]*Subscription)
+		}
+		g.bus.followers[sub.follow][sub.id] = sub
+	}
+	g.setSubscriptionArea(sub, area)
+	return sub
+}
+
+// Events - Channel nhận event
+func (s *Subscription) Events() <-chan WorldEvent {
+	return s.ch
+}
+
+// Dropped - Tổng số event bị bỏ vì buffer đầy
+func (s *Subscription) Dropped() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// Lagged - Subscription bị đóng vì subscriber quá chậm (chính sách CloseOnLag)
+func (s *Subscription) Lagged() bool {
+	return s.lagged.Load()
+}
+
+// Close - Hủy đăng ký và đóng channel
+func (s *Subscription) Close() {
+	s.closeOne.Do(func() {
+		g := s.grid
+		g.bus.mu.Lock()
+		g.removeSubscription(s)
+		g.bus.mu.Unlock()
+
+		s.mu.Lock()
+		if !s.closed {
+			s.closed = true
+			close(s.ch)
+		}
+		s.mu.Unlock()
+	})
+}
+
+// removeSubscription - Gỡ subscriber khỏi mọi chỉ mục (cần giữ bus.mu)
+func (g *Grid) removeSubscription(sub *Subscription) {
+	g.setSubscriptionArea(sub, nil)
+	delete(g.bus.global, sub.id)
+	delete(g.bus.subs, sub.id)
+	if followers, exists := g.bus.followers[sub.follow]; exists {
+		delete(followers, sub.id)
+		if len(followers) == 0 {
+			delete(g.bus.followers, sub.follow)
+		}
+	}
+}
+
+// setSubscriptionArea - Đổi vùng quan tâm và cập nhật chỉ mục chunk (cần giữ bus.mu)
+func (g *Grid) setSubscriptionArea(sub *Subscription, area *Rect) {
+	for _, key := range sub.chunks {
+		delete(g.bus.byChunk[key], sub.id)
+		if len(g.bus.byChunk[key]) == 0 {
+			delete(g.bus.byChunk, key)
+		}
+	}
+	sub.chunks = nil
+	sub.area = area
+
+	if _, registered := g.bus.subs[sub.id]; !registered {
+		return
+	}
+	if area == nil {
+		g.bus.global[sub.id] = sub
+		return
+	}
+	delete(g.bus.global, sub.id)
+	for _, ys := range g.spans(area.Y, area.Height, g.height) {
+		for _, xs := range g.spans(area.X, area.Width, g.width) {
+			for cy := ys.from / constants.WorldChunkSize; cy*constants.WorldChunkSize < ys.to; cy++ {
+				for cx := xs.from / constants.WorldChunkSize; cx*constants.WorldChunkSize < xs.to; cx++ {
+					key := g.chunkIndex(cx, cy)
+					if g.bus.byChunk[key] == nil {
+						g.bus.byChunk[key] = make(map[uint64]*Subscription)
+					}
+					g.bus.byChunk[key][sub.id] = sub
+					sub.chunks = append(sub.chunks, key)
+				}
+			}
+		}
+	}
+}
+
+// followArea - Vùng vuông bán kính radius quanh vị trí pos
+func followArea(pos models.Position, radius int) *Rect {
+	return &Rect{X: pos.X - radius, Y: pos.Y - radius, Width: 2*radius + 1, Height: 2*radius + 1}
+}
+
+// contains - Ô (x, y) nằm trong vùng area, có tính wrap-around
+func (g *Grid) contains(area Rect, x, y int) bool {
+	within := func(v, start, length, size int) bool {
+		if !g.wrap {
+			return v >= start && v < start+length
+		}
+		return length >= size || ((v-start)%size+size)%size < length
+	}
+	return within(x, area.X, area.Width, g.width) && within(y, area.Y, area.Height, g.height)
+}
+
+// publish - Gửi event tới subscriber có vùng quan tâm chứa ô của event (hoặc ô xuất phát với event di chuyển).
+// Không được gọi khi đang giữ lock của ô.
+func (g *Grid) publish(event WorldEvent) {
+	event.Seq = atomic.AddUint64(&g.bus.seq, 1)
+	event.Time = g.clock.Now()
+
+	// Vùng của subscriber theo player được cập nhật trước khi lọc
+	if event.Type == WorldEventMove || event.Type == WorldEventPlayerEnter {
+		g.bus.mu.RLock()
+		_, followed := g.bus.followers[event.PlayerID]
+		g.bus.mu.RUnlock()
+		if followed {
+			g.bus.mu.Lock()
+			for _, sub := range g.bus.followers[event.PlayerID] {
+				g.setSubscriptionArea(sub, followArea(models.Position{X: event.X, Y: event.Y}, sub.radius))
+			}
+			g.bus.mu.Unlock()
+		}
+	}
+
+	g.bus.mu.RLock()
+	targets := make([]*Subscription, 0, len(g.bus.global))
+	for _, sub := range g.bus.global {
+		targets = append(targets, sub)
+	}
+	seen := make(map[uint64]bool)
+	points := [][2]int{{event.X, event.Y}}
+	if event.Type == WorldEventMove {
+		points = append(points, [2]int{event.FromX, event.FromY})
+	}
+	for _, point := range points {
+		key, _ := g.chunkKey(point[0], point[1])
+		for id, sub := range g.bus.byChunk[key] {
+			if seen[id] {
+				continue
+			}
+			if g.contains(*sub.area, event.X, event.Y) ||
+				(event.Type == WorldEventMove && g.contains(*sub.area, event.FromX, event.FromY)) {
+				seen[id] = true
+				targets = append(targets, sub)
+			}
+		}
+	}
+	g.bus.mu.RUnlock()
+
+	for _, sub := range targets {
+		sub.deliver(event)
+	}
+}
+
+// deliver - Đưa event vào buffer theo chính sách của subscriber
+func (s *Subscription) deliver(event WorldEvent) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+
+	select {
+	case s.ch <- event:
+		s.mu.Unlock()
+		return
+	default:
+	}
+
+	s.dropped++
+	switch s.policy {
+	case DropOldest:
+		select {
+		case <-s.ch:
+		default:
+		}
+		select {
+		case s.ch <- event:
+		default:
+		}
+		s.mu.Unlock()
+	case CloseOnLag:
+		s.lagged.Store(true)
+		s.mu.Unlock()
+		go s.Close()
+	default:
+		s.mu.Unlock()
+	}
+}
+
+// closeSubscriptions - Đóng mọi subscription khi grid dừng
+func (g *Grid) closeSubscriptions() {
+	g.bus.mu.RLock()
+	subs := make([]*Subscription, 0, len(g.bus.subs))
+	for _, sub := range g.bus.subs {
+		subs = append(subs, sub)
+	}
+	g.bus.mu.RUnlock()
+
+	for _, sub := range subs {
+		sub.Close()
+	}
+}
+
+// pokemonEvent - Event về một Pokemon hoang dã tại (x, y)
+func pokemonEvent(eventType WorldEventType, x, y int, pokemon *models.Pokemon) WorldEvent {
+	return WorldEvent{
+		Type:          eventType,
+		X:             x,
+		Y:             y,
+		PokemonNumber: pokemon.Number,
+		PokemonName:   pokemon.Name,
+		PokemonLevel:  pokemon.Level,
+	}
+}
@@ -0,0 +1,159 @@
+package pokecat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// spawnEvents - Đặt Pokemon vào từng ô và publish event spawn tương ứng
+func spawnEvents(t *testing.T, grid *Grid, cells ...models.Position) {
+	t.Helper()
+	for _, pos := range cells {
+		pokemon := newTestPokemon(t, "Pikachu")
+		grid.placePokemon(pos.X, pos.Y, pokemon)
+		grid.publish(pokemonEvent(WorldEventSpawn, pos.X, pos.Y, pokemon))
+	}
+}
+
+// pending - Các event đang nằm trong buffer, không chờ
+func pending(sub *Subscription) []WorldEvent {
+	var events []WorldEvent
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func eventCells(events []WorldEvent) []models.Position {
+	cells := make([]models.Position, len(events))
+	for i, event := range events {
+		cells[i] = models.Position{X: event.X, Y: event.Y}
+	}
+	return cells
+}
+
+var fiveCells = []models.Position{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0}}
+
+func TestDropNewestKeepsFirstEvents(t *testing.T) {
+	grid, _ := newTestGrid(t, testConfig(10, 10), 1)
+	sub := grid.Subscribe(SubscribeOptions{Buffer: 2, Policy: DropNewest})
+	defer sub.Close()
+
+	spawnEvents(t, grid, fiveCells...)
+
+	got := eventCells(pending(sub))
+	want := fiveCells[:2]
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("received %v, want %v", got, want)
+	}
+	if dropped := sub.Dropped(); dropped != 3 {
+		t.Errorf("dropped %d events, want 3", dropped)
+	}
+	if sub.Lagged() {
+		t.Error("DropNewest subscription reported lag")
+	}
+}
+
+func TestDropOldestKeepsLatestEvents(t *testing.T) {
+	grid, _ := newTestGrid(t, testConfig(10, 10), 1)
+	sub := grid.Subscribe(SubscribeOptions{Buffer: 2, Policy: DropOldest})
+	defer sub.Close()
+
+	spawnEvents(t, grid, fiveCells...)
+
+	got := eventCells(pending(sub))
+	want := fiveCells[3:]
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("received %v, want %v", got, want)
+	}
+	if dropped := sub.Dropped(); dropped != 3 {
+		t.Errorf("dropped %d events, want 3", dropped)
+	}
+}
+
+// TestCloseOnLagResync - Subscriber chậm bị đóng, sau đó lấy lại view và đăng ký lại
+// (như forwardWorldEvents) thì thấy đủ trạng thái và tiếp tục nhận event mới.
+func TestCloseOnLagResync(t *testing.T) {
+	grid, _ := newTestGrid(t, testConfig(10, 10), 1)
+	area := Rect{X: 0, Y: 0, Width: 5, Height: 5}
+	sub := grid.Subscribe(SubscribeOptions{Area: &area, Buffer: 2, Policy: CloseOnLag})
+
+	spawnEvents(t, grid, fiveCells...)
+
+	// Channel được đóng bất đồng bộ, đọc hết buffer cho tới khi đóng
+	timeout := time.After(time.Second)
+	received := 0
+	for closed := false; !closed; {
+		select {
+		case _, ok := <-sub.Events():
+			if ok {
+				received++
+			} else {
+				closed = true
+			}
+		case <-timeout:
+			t.Fatal("lagging subscription was not closed")
+		}
+	}
+	if !sub.Lagged() {
+		t.Error("closed subscription does not report lag")
+	}
+	if received != 2 {
+		t.Errorf("received %d events before close, want 2", received)
+	}
+
+	resub := grid.Subscribe(SubscribeOptions{Area: &area, Buffer: 2, Policy: CloseOnLag})
+	defer resub.Close()
+	view := grid.QueryRect(area)
+	if len(view.Pokemon) != len(fiveCells) {
+		t.Errorf("resync view has %d pokemon, want %d", len(view.Pokemon), len(fiveCells))
+	}
+
+	spawnEvents(t, grid, models.Position{X: 4, Y: 4})
+	if got := eventCells(pending(resub)); len(got) != 1 || got[0] != (models.Position{X: 4, Y: 4}) {
+		t.Errorf("resubscribed received %v, want [(4,4)]", got)
+	}
+	if resub.Lagged() {
+		t.Error("resubscription reported lag")
+	}
+}
+
+// TestFollowAreaMovesWithPlayer - Vùng theo player dịch chuyển theo từng bước đi
+func TestFollowAreaMovesWithPlayer(t *testing.T) {
+	grid, clk := newTestGrid(t, testConfig(20, 20), 1)
+	player := newTestPlayer(t, grid, clk, "follower", 2, 2)
+	sub := grid.Subscribe(SubscribeOptions{Follow: player, Radius: 1})
+	defer sub.Close()
+
+	for i := 0; i < 4; i++ {
+		if _, err := grid.move(player, constants.DirectionRight, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	moves := pending(sub)
+	if len(moves) != 4 {
+		t.Fatalf("received %d events while moving, want 4 moves", len(moves))
+	}
+	for _, event := range moves {
+		if event.Type != WorldEventMove || event.PlayerID != "follower" {
+			t.Errorf("unexpected event %+v while moving", event)
+		}
+	}
+
+	// Player đang ở (6, 2): ô cũ nằm ngoài vùng, ô cạnh vị trí mới nằm trong vùng
+	spawnEvents(t, grid, models.Position{X: 2, Y: 2}, models.Position{X: 7, Y: 3})
+	got := eventCells(pending(sub))
+	if len(got) != 1 || got[0] != (models.Position{X: 7, Y: 3}) {
+		t.Errorf("received %v after moving, want only [(7,3)]", got)
+	}
+}
@@ -0,0 +1,92 @@
+package pokecat
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/clock"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/config"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/database"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/random"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/testutil"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.RunInTempDir(m))
+}
+
+var testStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// testConfig - World nhỏ để kiểm thử, các giá trị khác theo mặc định
+func testConfig(width, height int) config.Config {
+	cfg := config.Default()
+	cfg.World.Width = width
+	cfg.World.Height = height
+	return cfg
+}
+
+// newTestGrid - Grid chạy theo đồng hồ giả với seed cố định, dọn dẹp khi test kết thúc
+func newTestGrid(t testing.TB, cfg config.Config, seed int64) (*Grid, *clock.Fake) {
+	t.Helper()
+	clk := clock.NewFake(testStart)
+	grid := NewGridWithSources(cfg, clk, random.New(seed))
+	t.Cleanup(grid.Cleanup)
+	return grid, clk
+}
+
+// newTestPlayer - Player đặt tại (x, y) và đã vào world
+func newTestPlayer(t testing.TB, grid *Grid, clk clock.Clock, id string, x, y int) *models.Player {
+	t.Helper()
+	cfg := testConfig(grid.width, grid.height)
+	player := models.NewPlayerWithSources(id, cfg, clk, random.New(1))
+	player.SetPosition(models.Position{X: x, Y: y})
+	if err := grid.AddPlayer(player); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { player.Cleanup() })
+	return player
+}
+
+// newTestPokemon - Pokemon level 5 của loài name theo Pokedex
+func newTestPokemon(t testing.TB, name string) *models.Pokemon {
+	t.Helper()
+	pokedex, err := database.GetPokedex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := pokedex.Find(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pokemon, err := models.NewPokemon(entry.ToMap(), 5, constants.DefaultEV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pokemon
+}
+
+// playerCells - Ô chứa từng player, quét toàn bộ world
+func playerCells(grid *Grid) map[string][]models.Position {
+	cells := make(map[string][]models.Position)
+	for y := 0; y < grid.height; y++ {
+		for x := 0; x < grid.width; x++ {
+			cell, release, err := grid.pinCell(x, y, false)
+			if err != nil {
+				panic(fmt.Sprintf("pin (%d,%d): %v", x, y, err))
+			}
+			if cell != nil {
+				cell.mu.RLock()
+				for id := range cell.Players {
+					cells[id] = append(cells[id], models.Position{X: x, Y: y})
+				}
+				cell.mu.RUnlock()
+			}
+			release()
+		}
+	}
+	return cells
+}
@@ -0,0 +1,154 @@
+package pokecat
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// MoveResult - Vị trí mới của player và những gì xảy ra khi bước vào ô
+type MoveResult struct {
+	Position  models.Position
+	Captures  []CaptureEvent // Pokemon tự động bắt được
+	Encounter *Encounter     // Battle hoang dã vừa bắt đầu (chế độ gặp Pokemon hoang dã)
+}
+
+// Move - Di chuyển player một ô theo hướng: kiểm tra hướng và tốc độ, áp dụng wrap-around hoặc
+// chặn ở mép world, cập nhật cùng lúc ô trong grid và vị trí của player.
+// Di chuyển thủ công hợp lệ sẽ tắt chế độ tự động di chuyển.
+func (g *Grid) Move(player *models.Player, direction constants.Direction) (MoveResult, error) {
+	return g.move(player, direction, true)
+}
+
+// move - Một bước di chuyển; bước tự động do routine của grid điều nhịp nên không kiểm tra tốc độ
+func (g *Grid) move(player *models.Player, direction constants.Direction, manual bool) (MoveResult, error) {
+	// Mỗi player chỉ đi một bước tại một thời điểm để giới hạn tốc độ không bị vượt qua
+	mover := g.moverLock(player.GetID())
+	mover.Lock()
+	defer mover.Unlock()
+
+	if manual {
+		if err := player.CanMove(); err != nil {
+			return MoveResult{}, err
+		}
+	}
+	oldPos := player.GetPosition()
+	newPos, err := g.step(oldPos, direction)
+	if err != nil {
+		return MoveResult{}, err
+	}
+	// Bước thủ công bị từ chối (chưa hết thời gian chờ, hướng không đi được) giữ nguyên chế độ tự động
+	if manual {
+		g.StopAutoMode(player.GetID())
+	}
+
+	oldCell, releaseOld, err := g.pinCell(oldPos.X, oldPos.Y, false)
+	if err != nil {
+		return MoveResult{}, err
+	}
+	defer releaseOld()
+	if oldCell == nil {
+		return MoveResult{}, fmt.Errorf("player %s is not in the world", player.GetID())
+	}
+	newCell, releaseNew, _ := g.pinCell(newPos.X, newPos.Y, true)
+	defer releaseNew()
+	encounters := g.encountersEnabled()
+
+	// Lock cells theo thứ tự để tránh deadlock
+	unlock := lockCells(oldPos, oldCell, newPos, newCell)
+	if _, exists := oldCell.Players[player.GetID()]; !exists {
+		unlock()
+		return MoveResult{}, fmt.Errorf("player %s is not in the world", player.GetID())
+	}
+	delete(oldCell.Players, player.GetID())
+	newCell.Players[player.GetID()] = player
+	player.SetPosition(newPos)
+
+	// Bắt Pokemon khi vẫn giữ lock của ô - hai player vào cùng lúc thì chỉ một người bắt được
+	result := MoveResult{Position: newPos}
+	if !encounters {
+		result.Captures = g.captureAll(player, newPos.X, newPos.Y, newCell)
+	}
+	unlock()
+
+	// Inventory chỉ được ghi file sau khi thả lock của ô
+	if len(result.Captures) > 0 {
+		if err := player.Save(); err != nil {
+			log.Printf("Failed to save player %s after capture: %v", player.GetID(), err)
+		}
+	}
+
+	g.publish(WorldEvent{
+		Type:     WorldEventMove,
+		X:        newPos.X,
+		Y:        newPos.Y,
+		FromX:    oldPos.X,
+		FromY:    oldPos.Y,
+		PlayerID: player.GetID(),
+	})
+	if encounters {
+		result.Encounter = g.encounterAt(player, newPos.X, newPos.Y)
+	}
+	g.notifyCaptures(result.Captures)
+	return result, nil
+}
+
+// step - Vị trí kế tiếp theo hướng đi
+func (g *Grid) step(pos models.Position, direction constants.Direction) (models.Position, error) {
+	switch direction {
+	case constants.DirectionUp:
+		pos.Y--
+	case constants.DirectionDown:
+		pos.Y++
+	case constants.DirectionLeft:
+		pos.X--
+	case constants.DirectionRight:
+		pos.X++
+	default:
+		return pos, fmt.Errorf("invalid direction")
+	}
+
+	if g.wrap {
+		pos.X = (pos.X + g.width) % g.width
+		pos.Y = (pos.Y + g.height) % g.height
+	}
+	if !g.isValidPosition(pos.X, pos.Y) {
+		return pos, fmt.Errorf(constants.ErrInvalidMove)
+	}
+	if !g.terrainAt(pos.X, pos.Y).Passable() {
+		return pos, fmt.Errorf(constants.ErrImpassableTerrain)
+	}
+	return pos, nil
+}
+
+func (g *Grid) moverLock(playerID string) *sync.Mutex {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	mover, exists := g.movers[playerID]
+	if !exists {
+		mover = &sync.Mutex{}
+		g.movers[playerID] = mover
+	}
+	return mover
+}
+
+// lockCells - Lock hai ô theo thứ tự hàng rồi cột, trả về hàm unlock
+func lockCells(aPos models.Position, a *Cell, bPos models.Position, b *Cell) func() {
+	if a == b {
+		a.mu.Lock()
+		return a.mu.Unlock
+	}
+	if bPos.Y < aPos.Y || (bPos.Y == aPos.Y && bPos.X < aPos.X) {
+		a, b = b, a
+	}
+	a.mu.Lock()
+	b.mu.Lock()
+	return func() {
+		b.mu.Unlock()
+		a.mu.Unlock()
+	}
+}
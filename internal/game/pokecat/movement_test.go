@@ -0,0 +1,105 @@
+package pokecat
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// TestConcurrentMovesKeepCellIndex - Nhiều player đi cùng lúc trong world nhỏ (thường xuyên chung ô
+// và đi qua mép), sau đó mỗi player nằm đúng một ô và đó là ô theo vị trí đã lưu. Chạy với -race.
+func TestConcurrentMovesKeepCellIndex(t *testing.T) {
+	const players, steps = 16, 300
+	grid, clk := newTestGrid(t, testConfig(12, 12), 1)
+
+	list := make([]*models.Player, players)
+	for i := range list {
+		list[i] = newTestPlayer(t, grid, clk, fmt.Sprintf("mover-%d", i), i%4, i/4)
+	}
+
+	directions := []constants.Direction{
+		constants.DirectionUp, constants.DirectionDown, constants.DirectionLeft, constants.DirectionRight,
+	}
+	var wg sync.WaitGroup
+	for i, player := range list {
+		wg.Add(1)
+		go func(player *models.Player, seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for s := 0; s < steps; s++ {
+				if _, err := grid.move(player, directions[rng.Intn(len(directions))], false); err != nil {
+					t.Errorf("move %s: %v", player.GetID(), err)
+					return
+				}
+			}
+		}(player, int64(i))
+	}
+	wg.Wait()
+
+	cells := playerCells(grid)
+	for _, player := range list {
+		found := cells[player.GetID()]
+		if len(found) != 1 {
+			t.Errorf("%s is indexed in %d cells %v, want exactly 1", player.GetID(), len(found), found)
+			continue
+		}
+		if pos := player.GetPosition(); found[0] != pos {
+			t.Errorf("%s is indexed at %v but stored position is %v", player.GetID(), found[0], pos)
+		}
+	}
+	if len(cells) != players {
+		t.Errorf("%d players indexed, want %d", len(cells), players)
+	}
+}
+
+func TestMoveWrapsAroundEdges(t *testing.T) {
+	grid, clk := newTestGrid(t, testConfig(8, 8), 1)
+	player := newTestPlayer(t, grid, clk, "wrapper", 0, 0)
+
+	result, err := grid.move(player, constants.DirectionLeft, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (models.Position{X: 7, Y: 0}); result.Position != want || player.GetPosition() != want {
+		t.Errorf("position after wrapping left = %v (stored %v), want %v", result.Position, player.GetPosition(), want)
+	}
+}
+
+// TestManualMoveStopsAutoModeOnlyWhenAccepted - Nước đi thủ công bị từ chối vì chưa hết thời gian chờ
+// giữ nguyên chế độ tự động, nước đi hợp lệ thì tắt nó
+func TestManualMoveStopsAutoModeOnlyWhenAccepted(t *testing.T) {
+	grid, clk := newTestGrid(t, testConfig(8, 8), 1)
+	player := newTestPlayer(t, grid, clk, "manual-over-auto", 3, 3)
+	// Bước tự động cũng ghi nhận lần di chuyển, tắt routine để chỉ còn nước đi thủ công
+	grid.autoTick.Stop()
+	if err := grid.StartAutoMode(player, time.Minute, constants.AutoStrategyRandom); err != nil {
+		t.Fatal(err)
+	}
+	inAutoMode := func() bool {
+		grid.mu.RLock()
+		_, registered := grid.autoMode[player.GetID()]
+		grid.mu.RUnlock()
+		return registered && player.GetAutoMode().IsActive(clk.Now())
+	}
+
+	// Player vừa được đặt vào world nên còn trong thời gian chờ
+	if _, err := grid.Move(player, constants.DirectionRight); err == nil {
+		t.Fatal("manual move during the cooldown was accepted")
+	}
+	if !inAutoMode() {
+		t.Error("rejected manual move stopped auto mode")
+	}
+
+	clk.Advance(time.Second / constants.MovementSpeed)
+	if _, err := grid.Move(player, constants.DirectionRight); err != nil {
+		t.Fatalf("manual move after the cooldown: %v", err)
+	}
+	if inAutoMode() {
+		t.Error("accepted manual move left auto mode running")
+	}
+}
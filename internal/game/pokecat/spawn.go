@@ -1,7 +1,6 @@
 package pokecat
 
 import (
-	"math/rand"
 	"time"
 
 	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
@@ -9,12 +8,12 @@ import (
 )
 
 func (g *Grid) startSpawnRoutine() {
-	g.spawnTick = time.NewTicker(time.Duration(constants.SpawnInterval) * time.Second)
+	g.spawnTick = g.clock.NewTicker(time.Duration(g.spawn.SpawnInterval) * time.Second)
 
 	go func() {
 		for {
 			select {
-			case <-g.spawnTick.C:
+			case <-g.spawnTick.C():
 				g.spawnPokemonWave()
 			case <-g.done:
 				g.spawnTick.Stop()
@@ -25,47 +24,36 @@ func (g *Grid) startSpawnRoutine() {
 }
 
 func (g *Grid) spawnPokemonWave() {
-	for i := 0; i < constants.SpawnCount; i++ {
-		// Random position
-		x := rand.Intn(g.width)
-		y := rand.Intn(g.height)
+	for i := 0; i < g.spawn.SpawnCount; i++ {
+		// Random position trên địa hình đi được
+		x, y, found := g.spawnPosition()
+		if !found {
+			continue
+		}
 
 		// Random level và EV
-		level := rand.Intn(constants.MaxLevel) + 1
-		ev := constants.MinEV + rand.Float64()*(constants.MaxEV-constants.MinEV)
+		level := g.rng.Intn(constants.MaxLevel) + 1
+		ev := constants.MinEV + g.rng.Float64()*(constants.MaxEV-constants.MinEV)
 
-		pokemon, err := models.NewRandomPokemon(level, ev)
+		// Loài được chọn theo bảng spawn của biome
+		pokemon, err := models.NewRandomPokemonWeighted(g.rng, level, ev, g.spawnWeight(x, y))
 		if err != nil {
 			continue
 		}
 
-		cell, _ := g.GetCell(x, y)
-		cell.mu.Lock()
-		cell.Pokemon[pokemon.Number] = pokemon
-		cell.mu.Unlock()
+		g.placePokemon(x, y, pokemon)
+		g.publish(pokemonEvent(WorldEventSpawn, x, y, pokemon))
 
 		// Schedule despawn
-		go g.scheduleDespawn(x, y, pokemon.Number)
+		g.scheduleDespawn(x, y, pokemon)
 	}
 }
 
-func (g *Grid) scheduleDespawn(x, y int, pokemonNumber string) {
-	time.Sleep(time.Duration(constants.DespawnTime) * time.Second)
-
-	cell, err := g.GetCell(x, y)
-	if err != nil {
-		return
-	}
-
-	cell.mu.Lock()
-	delete(cell.Pokemon, pokemonNumber)
-	cell.mu.Unlock()
-}
-
 func (g *Grid) isValidPosition(x, y int) bool {
 	return x >= 0 && x < g.width && y >= 0 && y < g.height
 }
 
 func (g *Grid) Cleanup() {
 	close(g.done)
+	g.closeSubscriptions()
 }
@@ -0,0 +1,85 @@
+package pokecat
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/clock"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/config"
+)
+
+// waitFor - Chờ routine chạy nền của grid đạt điều kiện sau khi đồng hồ giả được tiến
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// spawnSnapshot - Vị trí, loài và level của mọi Pokemon trong world
+func spawnSnapshot(grid *Grid) []string {
+	view := grid.QueryRect(Rect{Width: grid.width, Height: grid.height})
+	snapshot := make([]string, 0, len(view.Pokemon))
+	for _, visible := range view.Pokemon {
+		snapshot = append(snapshot, fmt.Sprintf("%d,%d %s lv%d ev%.3f",
+			visible.X, visible.Y, visible.Pokemon.Name, visible.Pokemon.Level, visible.Pokemon.EV))
+	}
+	return snapshot
+}
+
+// runSpawnWave - Tiến đồng hồ tới đợt spawn kế tiếp và chờ routine spawn xong
+func runSpawnWave(t *testing.T, grid *Grid, clk *clock.Fake, cfg config.Config) {
+	t.Helper()
+	before := grid.despawns.pending()
+	clk.Advance(time.Duration(cfg.World.SpawnInterval) * time.Second)
+	waitFor(t, "spawn wave", func() bool { return grid.despawns.pending() == before+cfg.World.SpawnCount })
+}
+
+func TestSeededSpawnsAreDeterministic(t *testing.T) {
+	cfg := testConfig(200, 200)
+	cfg.World.SpawnCount = 40
+
+	snapshots := make([][]string, 0, 3)
+	for _, seed := range []int64{42, 42, 43} {
+		grid, clk := newTestGrid(t, cfg, seed)
+		runSpawnWave(t, grid, clk, cfg)
+		runSpawnWave(t, grid, clk, cfg)
+		snapshots = append(snapshots, spawnSnapshot(grid))
+	}
+
+	if len(snapshots[0]) != 2*cfg.World.SpawnCount {
+		t.Fatalf("%d pokemon after two waves, want %d", len(snapshots[0]), 2*cfg.World.SpawnCount)
+	}
+	if !reflect.DeepEqual(snapshots[0], snapshots[1]) {
+		t.Errorf("same seed produced different spawns:\n%v\n%v", snapshots[0], snapshots[1])
+	}
+	if reflect.DeepEqual(snapshots[0], snapshots[2]) {
+		t.Errorf("different seeds produced identical spawns")
+	}
+}
+
+func TestSpawnRoutineDespawnsOnSchedule(t *testing.T) {
+	cfg := testConfig(200, 200)
+	cfg.World.SpawnCount = 20
+	cfg.World.DespawnTime = 30
+	grid, clk := newTestGrid(t, cfg, 7)
+	runSpawnWave(t, grid, clk, cfg)
+
+	// Routine despawn chạy mỗi giây; tới trước hạn một giây vẫn còn đủ Pokemon
+	for i := 1; i < cfg.World.DespawnTime; i++ {
+		clk.Advance(time.Second)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if got := len(spawnSnapshot(grid)); got != cfg.World.SpawnCount {
+		t.Fatalf("%d pokemon left one second before despawn, want %d", got, cfg.World.SpawnCount)
+	}
+
+	clk.Advance(time.Second)
+	waitFor(t, "despawn", func() bool { return len(spawnSnapshot(grid)) == 0 && grid.despawns.pending() == 0 })
+}
@@ -0,0 +1,331 @@
+package pokecat
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/config"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/database"
+)
+
+// Terrain - Loại địa hình của một ô
+type Terrain uint8
+
+const (
+	TerrainGrass    Terrain = iota // Đồng cỏ
+	TerrainWater                   // Nước, không đi được
+	TerrainMountain                // Núi, không đi được
+	TerrainCave                    // Hang động
+	TerrainCity                    // Thành phố
+)
+
+// terrainSymbols - Ký tự của từng địa hình trong file map và trong vùng nhìn thấy gửi cho client
+var terrainSymbols = map[Terrain]byte{
+	TerrainGrass:    '.',
+	TerrainWater:    '~',
+	TerrainMountain: '^',
+	TerrainCave:     'o',
+	TerrainCity:     '#',
+}
+
+var terrainNames = map[Terrain]string{
+	TerrainGrass:    "grass",
+	TerrainWater:    "water",
+	TerrainMountain: "mountain",
+	TerrainCave:     "cave",
+	TerrainCity:     "city",
+}
+
+// biomeSpawnTables - Trọng số theo hệ Pokedex của từng biome, hệ không có trong bảng có trọng số 1.
+// Loài nhiều hệ lấy trọng số lớn nhất trong các hệ của nó.
+var biomeSpawnTables = map[Terrain]map[string]float64{
+	TerrainGrass:    {"Grass": 6, "Bug": 5, "Normal": 4, "Flying": 3, "Poison": 2},
+	TerrainWater:    {"Water": 8, "Ice": 2},
+	TerrainMountain: {"Rock": 6, "Ground": 6, "Fighting": 2, "Steel": 2, "Dragon": 2},
+	TerrainCave:     {"Rock": 5, "Ground": 4, "Dark": 4, "Ghost": 4, "Poison": 2, "Steel": 2},
+	TerrainCity:     {"Normal": 5, "Electric": 5, "Psychic": 4, "Steel": 3, "Fighting": 3, "Fairy": 2},
+}
+
+// String - Tên địa hình
+func (t Terrain) String() string {
+	if name, exists := terrainNames[t]; exists {
+		return name
+	}
+	return "unknown"
+}
+
+// Symbol - Ký tự biểu diễn địa hình
+func (t Terrain) Symbol() byte {
+	return terrainSymbols[t]
+}
+
+// Passable - Player có thể đi vào ô có địa hình này
+func (t Terrain) Passable() bool {
+	return t != TerrainWater && t != TerrainMountain
+}
+
+// TerrainMap - Địa hình của world: đọc từ file map hoặc sinh từ seed.
+// Địa hình sinh từ seed được tính khi cần nên không tốn bộ nhớ theo kích thước world.
+type TerrainMap struct {
+	width  int
+	height int
+	cells  []Terrain // Địa hình từ file map theo hàng, nil nếu sinh từ seed
+	seed   int64
+}
+
+// NewTerrain - Tạo địa hình theo cfg: file TerrainMap nếu có, không thì sinh từ TerrainSeed.
+// Trả về nil khi không cấu hình địa hình.
+func NewTerrain(cfg config.WorldConfig) (*TerrainMap, error) {
+	switch {
+	case cfg.TerrainMap != "":
+		return LoadTerrainMap(cfg.TerrainMap, cfg.Width, cfg.Height)
+	case cfg.TerrainSeed != 0:
+		return GenerateTerrain(cfg.Width, cfg.Height, int64(cfg.TerrainSeed)), nil
+	}
+	return nil, nil
+}
+
+// GenerateTerrain - Sinh địa hình từ seed: cùng seed và kích thước luôn cho cùng địa hình
+func GenerateTerrain(width, height int, seed int64) *TerrainMap {
+	return &TerrainMap{width: width, height: height, seed: seed}
+}
+
+// LoadTerrainMap - Đọc file map, mỗi dòng là một hàng của world và mỗi ký tự là một ô
+// ('.' cỏ, '~' nước, '^' núi, 'o' hang động, '#' thành phố). Dòng trống và dòng bắt đầu
+// bằng ';' được bỏ qua.
+func LoadTerrainMap(path string, width, height int) (*TerrainMap, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read terrain map: %v", err)
+	}
+	defer file.Close()
+
+	bySymbol := make(map[byte]Terrain, len(terrainSymbols))
+	for terrain, symbol := range terrainSymbols {
+		bySymbol[symbol] = terrain
+	}
+
+	terrain := &TerrainMap{width: width, height: height, cells: make([]Terrain, 0, width*height)}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), width+64*1024)
+	rows := 0
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if rows == height {
+			return nil, fmt.Errorf("terrain map %s has more than %d rows", path, height)
+		}
+		if len(line) != width {
+			return nil, fmt.Errorf("terrain map %s row %d has %d cells, expected %d", path, rows+1, len(line), width)
+		}
+		for i := 0; i < len(line); i++ {
+			cell, exists := bySymbol[line[i]]
+			if !exists {
+				return nil, fmt.Errorf("terrain map %s row %d has unknown terrain %q", path, rows+1, line[i])
+			}
+			terrain.cells = append(terrain.cells, cell)
+		}
+		rows++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read terrain map: %v", err)
+	}
+	if rows != height {
+		return nil, fmt.Errorf("terrain map %s has %d rows, expected %d", path, rows, height)
+	}
+	return terrain, nil
+}
+
+// At - Địa hình tại ô (x, y) hợp lệ
+func (t *TerrainMap) At(x, y int) Terrain {
+	if t.cells != nil {
+		return t.cells[y*t.width+x]
+	}
+	return t.generate(x, y)
+}
+
+// generate - Địa hình sinh từ độ cao, độ ẩm và mật độ dân cư (value noise theo seed)
+func (t *TerrainMap) generate(x, y int) Terrain {
+	elevation := 0.65*t.noise(x, y, 48, 1) + 0.35*t.noise(x, y, 12, 2)
+	moisture := t.noise(x, y, 32, 3)
+	settlement := t.noise(x, y, 20, 4)
+
+	switch {
+	case elevation < 0.34:
+		return TerrainWater
+	case elevation > 0.7:
+		return TerrainMountain
+	case elevation > 0.62 && moisture > 0.55:
+		return TerrainCave
+	case settlement > 0.78 && elevation > 0.4 && elevation < 0.58:
+		return TerrainCity
+	}
+	return TerrainGrass
+}
+
+// noise - Value noise trong [0, 1): giá trị ngẫu nhiên ở các điểm lưới cách nhau scale ô,
+// nội suy mượt giữa các điểm
+func (t *TerrainMap) noise(x, y, scale int, salt uint64) float64 {
+	gx, gy := x/scale, y/scale
+	fx := smoothstep(float64(x%scale) / float64(scale))
+	fy := smoothstep(float64(y%scale) / float64(scale))
+
+	top := lerp(t.lattice(gx, gy, salt), t.lattice(gx+1, gy, salt), fx)
+	bottom := lerp(t.lattice(gx, gy+1, salt), t.lattice(gx+1, gy+1, salt), fx)
+	return lerp(top, bottom, fy)
+}
+
+// lattice - Giá trị ngẫu nhiên cố định của điểm lưới (gx, gy) theo seed
+func (t *TerrainMap) lattice(gx, gy int, salt uint64) float64 {
+	h := mix(mix(mix(uint64(t.seed)^salt)^uint64(gx)) ^ uint64(gy))
+	return float64(h>>11) / float64(1<<53)
+}
+
+// mix - Bước trộn của splitmix64
+func mix(h uint64) uint64 {
+	h += 0x9e3779b97f4a7c15
+	h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+	h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+	return h ^ (h >> 31)
+}
+
+func smoothstep(v float64) float64 {
+	return v * v * (3 - 2*v)
+}
+
+func lerp(a, b, v float64) float64 {
+	return a + (b-a)*v
+}
+
+// SetTerrain - Gắn địa hình cho world, nil là không có địa hình.
+// Kích thước địa hình phải trùng kích thước world.
+func (g *Grid) SetTerrain(terrain *TerrainMap) error {
+	if terrain != nil && (terrain.width != g.width || terrain.height != g.height) {
+		return fmt.Errorf("terrain is %dx%d but world is %dx%d", terrain.width, terrain.height, g.width, g.height)
+	}
+	g.terrain.Store(terrain)
+	return nil
+}
+
+// TerrainAt - Địa hình tại (x, y); world không có địa hình thì mọi ô là đồng cỏ
+func (g *Grid) TerrainAt(x, y int) (Terrain, error) {
+	if !g.isValidPosition(x, y) {
+		return TerrainGrass, errInvalidPosition(x, y)
+	}
+	return g.terrainAt(x, y), nil
+}
+
+func (g *Grid) terrainAt(x, y int) Terrain {
+	terrain := g.terrain.Load()
+	if terrain == nil {
+		return TerrainGrass
+	}
+	return terrain.At(x, y)
+}
+
+// isPassable - Ô (x, y) hợp lệ và đi vào được
+func (g *Grid) isPassable(x, y int) bool {
+	return g.isValidPosition(x, y) && g.terrainAt(x, y).Passable()
+}
+
+// TerrainRows - Ký hiệu địa hình trong vùng area theo từng hàng, có tính wrap-around.
+// Ô nằm ngoài world (khi không wrap) là khoảng trắng.
+func (g *Grid) TerrainRows(area Rect) []string {
+	if area.Width <= 0 || area.Height <= 0 {
+		return nil
+	}
+	rows := make([]string, 0, area.Height)
+	row := make([]byte, area.Width)
+	for dy := 0; dy < area.Height; dy++ {
+		for dx := 0; dx < area.Width; dx++ {
+			x, y := area.X+dx, area.Y+dy
+			if g.wrap {
+				x, y = ((x%g.width)+g.width)%g.width, ((y%g.height)+g.height)%g.height
+			}
+			if !g.isValidPosition(x, y) {
+				row[dx] = ' '
+				continue
+			}
+			row[dx] = g.terrainAt(x, y).Symbol()
+		}
+		rows = append(rows, string(row))
+	}
+	return rows
+}
+
+// spawnWeight - Trọng số chọn loài khi spawn tại (x, y): bảng của biome tại ô cộng với bảng của
+// nước, núi trong phạm vi BiomeInfluenceRadius. Trả về nil khi world không có địa hình (chọn đều).
+func (g *Grid) spawnWeight(x, y int) func(*database.PokedexEntry) float64 {
+	if g.terrain.Load() == nil {
+		return nil
+	}
+
+	biomes := map[Terrain]bool{g.terrainAt(x, y): true}
+	radius := constants.BiomeInfluenceRadius
+	for _, ys := range g.spans(y-radius, 2*radius+1, g.height) {
+		for _, xs := range g.spans(x-radius, 2*radius+1, g.width) {
+			for cy := ys.from; cy < ys.to; cy++ {
+				for cx := xs.from; cx < xs.to; cx++ {
+					if terrain := g.terrainAt(cx, cy); !terrain.Passable() {
+						biomes[terrain] = true
+					}
+				}
+			}
+		}
+	}
+
+	tables := make([]map[string]float64, 0, len(biomes))
+	for terrain := range biomes {
+		tables = append(tables, biomeSpawnTables[terrain])
+	}
+	return func(entry *database.PokedexEntry) float64 {
+		weight := 1.0
+		for _, table := range tables {
+			for _, pokemonType := range entry.Types {
+				weight = max(weight, table[pokemonType])
+			}
+		}
+		return weight
+	}
+}
+
+// spawnPosition - Chọn vị trí spawn ngẫu nhiên trên địa hình đi được
+func (g *Grid) spawnPosition() (int, int, bool) {
+	for attempt := 0; attempt < constants.SpawnPositionAttempts; attempt++ {
+		x, y := g.rng.Intn(g.width), g.rng.Intn(g.height)
+		if g.isPassable(x, y) {
+			return x, y, true
+		}
+	}
+	return 0, 0, false
+}
+
+// nearestPassable - Ô đi được gần (x, y) nhất theo khoảng cách Chebyshev, tìm trong
+// bán kính TerrainRelocateRadius
+func (g *Grid) nearestPassable(x, y int) (int, int, bool) {
+	if g.isPassable(x, y) {
+		return x, y, true
+	}
+	for radius := 1; radius <= constants.TerrainRelocateRadius; radius++ {
+		for dy := -radius; dy <= radius; dy++ {
+			for dx := -radius; dx <= radius; dx++ {
+				if max(abs(dx), abs(dy)) != radius {
+					continue
+				}
+				cx, cy := x+dx, y+dy
+				if g.wrap {
+					cx, cy = ((cx%g.width)+g.width)%g.width, ((cy%g.height)+g.height)%g.height
+				}
+				if g.isPassable(cx, cy) {
+					return cx, cy, true
+				}
+			}
+		}
+	}
+	return x, y, false
+}
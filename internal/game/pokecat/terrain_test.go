@@ -0,0 +1,144 @@
+package pokecat
+
+import (
+	"os"
+	"testing"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/database"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// Địa hình seed 7 trên world 40x20 (wrap-around), mỗi ký tự là một ô:
+//
+//	y=0   ^^^^^^^^^^^^^...........................
+//	y=5   ^^^^^^^^^^^^^^^.........................
+//	y=9   ^^^^^^^^^^^^^^^^..............~~~~~~~~~~
+//	y=12  ^^^^^^^^^^^^^^^^..........~~~~~~~~~~~~~~
+//	y=18  ^^^^^^^^^^oooooo................~~~~~~~~
+//	y=19  ^^^^^^^^oooooo..................~~~~~~~~
+const (
+	testTerrainSeed   = 7
+	testTerrainWidth  = 40
+	testTerrainHeight = 20
+)
+
+func newTerrainGrid(t *testing.T) *Grid {
+	t.Helper()
+	grid, _ := newTestGrid(t, testConfig(testTerrainWidth, testTerrainHeight), 1)
+	if err := grid.SetTerrain(GenerateTerrain(testTerrainWidth, testTerrainHeight, testTerrainSeed)); err != nil {
+		t.Fatal(err)
+	}
+	return grid
+}
+
+func findEntry(t *testing.T, name string) *database.PokedexEntry {
+	t.Helper()
+	pokedex, err := database.GetPokedex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := pokedex.Find(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entry
+}
+
+func TestSpawnWeight(t *testing.T) {
+	grid := newTerrainGrid(t)
+	tests := []struct {
+		name    string
+		x, y    int
+		weights map[string]float64
+	}{
+		{"open grass", 22, 3, map[string]float64{"Bulbasaur": 6, "Squirtle": 1, "Geodude": 1, "Pikachu": 1}},
+		{"grass near water", 25, 12, map[string]float64{"Bulbasaur": 6, "Squirtle": 8, "Geodude": 1}},
+		{"grass near mountain", 16, 5, map[string]float64{"Bulbasaur": 6, "Squirtle": 1, "Geodude": 6}},
+		{"water", 30, 12, map[string]float64{"Bulbasaur": 1, "Squirtle": 8, "Geodude": 1}},
+		{"mountain", 5, 5, map[string]float64{"Bulbasaur": 1, "Squirtle": 1, "Geodude": 6}},
+		{"cave near mountain", 14, 18, map[string]float64{"Gastly": 4, "Geodude": 6, "Pikachu": 1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			weight := grid.spawnWeight(test.x, test.y)
+			if weight == nil {
+				t.Fatal("spawnWeight is nil with terrain set")
+			}
+			for name, want := range test.weights {
+				if got := weight(findEntry(t, name)); got != want {
+					t.Errorf("%s weight at (%d,%d) = %v, want %v", name, test.x, test.y, got, want)
+				}
+			}
+		})
+	}
+
+	t.Run("no terrain", func(t *testing.T) {
+		plain, _ := newTestGrid(t, testConfig(testTerrainWidth, testTerrainHeight), 1)
+		if plain.spawnWeight(22, 3) != nil {
+			t.Error("spawnWeight without terrain is not nil (uniform)")
+		}
+	})
+}
+
+func TestNearestPassable(t *testing.T) {
+	grid := newTerrainGrid(t)
+	tests := []struct {
+		name string
+		from models.Position
+		want models.Position
+	}{
+		{"already passable", models.Position{X: 20, Y: 5}, models.Position{X: 20, Y: 5}},
+		{"mountain edge", models.Position{X: 14, Y: 5}, models.Position{X: 14, Y: 4}},
+		{"inside water", models.Position{X: 30, Y: 12}, models.Position{X: 27, Y: 9}},
+		{"inside mountain wraps to bottom row", models.Position{X: 5, Y: 5}, models.Position{X: 8, Y: 19}},
+		{"corner wraps to right edge", models.Position{X: 0, Y: 0}, models.Position{X: 39, Y: 0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			x, y, found := grid.nearestPassable(test.from.X, test.from.Y)
+			if !found {
+				t.Fatalf("no passable cell found from %v", test.from)
+			}
+			if got := (models.Position{X: x, Y: y}); got != test.want {
+				t.Errorf("nearest passable from %v = %v, want %v", test.from, got, test.want)
+			}
+			if !grid.isPassable(x, y) {
+				t.Errorf("(%d,%d) is not passable", x, y)
+			}
+		})
+	}
+
+	t.Run("all water", func(t *testing.T) {
+		if err := os.WriteFile("water.map", []byte("~~~\n~~~\n~~~\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		terrain, err := LoadTerrainMap("water.map", 3, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		flooded, _ := newTestGrid(t, testConfig(3, 3), 1)
+		if err := flooded.SetTerrain(terrain); err != nil {
+			t.Fatal(err)
+		}
+		if x, y, found := flooded.nearestPassable(1, 1); found {
+			t.Errorf("found passable cell (%d,%d) in an all-water world", x, y)
+		}
+	})
+}
+
+// TestSweepAvoidsTerrain - Chiến lược quét bị núi chặn thì đổi sang hướng đi được như các chiến lược khác
+func TestSweepAvoidsTerrain(t *testing.T) {
+	grid := newTerrainGrid(t)
+	pos := models.Position{X: 15, Y: 5} // Bên trái là núi
+	for steps := 0; steps <= 2*(constants.AutoSweepWidth+1); steps++ {
+		auto := models.AutoMode{Strategy: constants.AutoStrategySweep, Steps: steps}
+		direction := grid.autoDirection(pos, auto)
+		if grid.blockedByTerrain(pos, direction) {
+			t.Fatalf("step %d: sweep moves %v into impassable terrain", steps, direction)
+		}
+		if !grid.blockedByTerrain(pos, sweepDirection(steps)) && direction != sweepDirection(steps) {
+			t.Errorf("step %d: sweep turned %v although %v is open", steps, direction, sweepDirection(steps))
+		}
+	}
+}
@@ -3,10 +3,14 @@ package pokecat
 import (
 	"fmt"
 	"sync"
-	"time"
+	"sync/atomic"
 
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/clock"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/config"
 	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/game/pokebat"
 	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/random"
 )
 
 // Cell - Đại diện cho một ô trong world grid
@@ -20,47 +24,116 @@ type Cell struct {
 type Grid struct {
 	width     int
 	height    int
-	cells     [][]*Cell
+	wrap      bool                       // Đi qua mép world sang mép đối diện
+	spawn     config.WorldConfig         // Lịch spawn và despawn Pokemon
+	terrain   atomic.Pointer[TerrainMap] // Địa hình, nil là mọi ô đều là đồng cỏ
 	mu        sync.RWMutex
-	spawnTick *time.Ticker
+	clock     clock.Clock
+	rng       random.Source
+	spawnTick clock.Ticker
+	despawns  despawnScheduler // Hàng đợi despawn theo từng lần spawn
 	done      chan struct{}
+	movers    map[string]*sync.Mutex // Khóa di chuyển theo player, mỗi player đi từng bước một
+	autoTick  clock.Ticker
+	autoMode  map[string]*models.Player // Player đang tự động di chuyển
+
+	bus eventBus // Subscriber nhận event world theo vùng quan tâm
+
+	chunks      map[int]*chunk // Chunk được tạo khi cần và thu hồi khi trống
+	chunkMu     sync.RWMutex
+	reclaimTick clock.Ticker
+
+	// OnCapture - Hook báo khi player tự động bắt được Pokemon lúc bước vào ô
+	OnCapture func(CaptureEvent)
+
+	battles     *pokebat.Manager      // Khác nil khi bật chế độ gặp Pokemon hoang dã
+	battleRules *pokebat.BattleFormat // Thể thức của battle hoang dã
+	encounters  map[string]*Encounter // Pokemon đang bị khóa trong battle hoang dã
+	encounterMu sync.Mutex
+}
+
+// NewGrid - Tạo world theo kích thước và lịch spawn trong cfg
+func NewGrid(cfg config.Config) *Grid {
+	return NewGridWithSources(cfg, clock.Real(), random.Global())
 }
 
-func NewGrid() *Grid {
+// NewGridWithSources - Tạo world với các routine chạy theo clk và vị trí, loài spawn,
+// hướng đi tự động chọn bằng rng (ví dụ đồng hồ giả và seed cố định khi kiểm thử)
+func NewGridWithSources(cfg config.Config, clk clock.Clock, rng random.Source) *Grid {
 	grid := &Grid{
-		width:  constants.WorldWidth,
-		height: constants.WorldHeight,
-		cells:  make([][]*Cell, constants.WorldHeight),
-		done:   make(chan struct{}),
-	}
-
-	// Khởi tạo cells
-	for i := range grid.cells {
-		grid.cells[i] = make([]*Cell, constants.WorldWidth)
-		for j := range grid.cells[i] {
-			grid.cells[i][j] = &Cell{
-				Players: make(map[string]*models.Player),
-				Pokemon: make(map[string]*models.Pokemon),
-			}
-		}
+		width:    cfg.World.Width,
+		height:   cfg.World.Height,
+		wrap:     cfg.World.WrapAround,
+		spawn:    cfg.World,
+		clock:    clk,
+		rng:      rng,
+		done:     make(chan struct{}),
+		movers:   make(map[string]*sync.Mutex),
+		autoMode: make(map[string]*models.Player),
+		bus:      newEventBus(),
+
+		chunks:     make(map[int]*chunk),
+		encounters: make(map[string]*Encounter),
 	}
 
 	// Bắt đầu spawn routine
 	grid.startSpawnRoutine()
+	grid.startDespawnRoutine()
+	grid.startAutoModeRoutine()
+	grid.startReclaimRoutine()
 	return grid
 }
 
+// GetCell - Lấy bản sao của ô tại (x, y), ô chưa được tạo thì trả về ô trống.
+// Bản sao không giữ chunk trong bộ nhớ; cần thao tác trên chính ô đó thì dùng PinCell.
 func (g *Grid) GetCell(x, y int) (*Cell, error) {
-	if !g.isValidPosition(x, y) {
-		return nil, fmt.Errorf("invalid position: (%d,%d)", x, y)
+	cell, release, err := g.pinCell(x, y, false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	snapshot := &Cell{
+		Players: make(map[string]*models.Player),
+		Pokemon: make(map[string]*models.Pokemon),
+	}
+	if cell == nil {
+		return snapshot, nil
+	}
+	cell.mu.RLock()
+	defer cell.mu.RUnlock()
+	for id, player := range cell.Players {
+		snapshot.Players[id] = player
+	}
+	for number, pokemon := range cell.Pokemon {
+		snapshot.Pokemon[number] = pokemon
 	}
-	return g.cells[y][x], nil
+	return snapshot, nil
 }
 
-// AddPlayer - Thêm player vào world
+// PinCell - Lấy ô tại (x, y), tạo mới nếu chưa có. Ô được giữ (không bị thu hồi khi trống)
+// cho tới khi người gọi gọi release.
+func (g *Grid) PinCell(x, y int) (*Cell, func(), error) {
+	return g.pinCell(x, y, true)
+}
+
+func errInvalidPosition(x, y int) error {
+	return fmt.Errorf("invalid position: (%d,%d)", x, y)
+}
+
+// AddPlayer - Thêm player vào world. Player đứng trên địa hình không đi được (ví dụ sau khi
+// đổi map) được chuyển tới ô đi được gần nhất.
 func (g *Grid) AddPlayer(player *models.Player) error {
 	pos := player.GetPosition()
-	cell, err := g.GetCell(pos.X, pos.Y)
+	if g.isValidPosition(pos.X, pos.Y) && !g.isPassable(pos.X, pos.Y) {
+		x, y, found := g.nearestPassable(pos.X, pos.Y)
+		if !found {
+			return fmt.Errorf(constants.ErrImpassableTerrain)
+		}
+		pos = models.Position{X: x, Y: y}
+		player.SetPosition(pos)
+	}
+	cell, release, err := g.pinCell(pos.X, pos.Y, true)
 	if err != nil {
 		return err
 	}
@@ -68,30 +141,54 @@ func (g *Grid) AddPlayer(player *models.Player) error {
 	cell.mu.Lock()
 	cell.Players[player.GetID()] = player
 	cell.mu.Unlock()
+	release()
+	g.publish(WorldEvent{Type: WorldEventPlayerEnter, X: pos.X, Y: pos.Y, PlayerID: player.GetID()})
+
+	// Player kết nối lại khi chế độ tự động còn hiệu lực thì tiếp tục di chuyển
+	if player.GetAutoMode().IsActive(g.clock.Now()) {
+		g.mu.Lock()
+		g.autoMode[player.GetID()] = player
+		g.mu.Unlock()
+	}
 	return nil
 }
 
 // RemovePlayer - Xóa player khỏi world
 func (g *Grid) RemovePlayer(player *models.Player) error {
 	pos := player.GetPosition()
-	cell, err := g.GetCell(pos.X, pos.Y)
+	cell, release, err := g.pinCell(pos.X, pos.Y, false)
 	if err != nil {
 		return err
 	}
+	if cell != nil {
+		cell.mu.Lock()
+		_, present := cell.Players[player.GetID()]
+		delete(cell.Players, player.GetID())
+		cell.mu.Unlock()
+		if present {
+			g.publish(WorldEvent{Type: WorldEventPlayerLeave, X: pos.X, Y: pos.Y, PlayerID: player.GetID()})
+		}
+	}
+	release()
 
-	cell.mu.Lock()
-	delete(cell.Players, player.GetID())
-	cell.mu.Unlock()
+	g.mu.Lock()
+	delete(g.movers, player.GetID())
+	delete(g.autoMode, player.GetID())
+	g.mu.Unlock()
 	return nil
 }
 
 // GetNearbyPokemons - Lấy danh sách Pokemon trong phạm vi có thể bắt
 func (g *Grid) GetNearbyPokemons(x, y int) map[string]*models.Pokemon {
 	result := make(map[string]*models.Pokemon)
-	cell, err := g.GetCell(x, y)
+	cell, release, err := g.pinCell(x, y, false)
 	if err != nil {
 		return result
 	}
+	defer release()
+	if cell == nil {
+		return result
+	}
 
 	cell.mu.RLock()
 	for num, pokemon := range cell.Pokemon {
@@ -103,10 +200,14 @@ func (g *Grid) GetNearbyPokemons(x, y int) map[string]*models.Pokemon {
 
 // CatchPokemon - Bắt Pokemon tại vị trí chỉ định
 func (g *Grid) CatchPokemon(x, y int, pokemonNumber string) (*models.Pokemon, error) {
-	cell, err := g.GetCell(x, y)
+	cell, release, err := g.pinCell(x, y, false)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
+	if cell == nil {
+		return nil, fmt.Errorf("pokemon not found at position (%d,%d)", x, y)
+	}
 
 	cell.mu.Lock()
 	defer cell.mu.Unlock()
@@ -121,29 +222,14 @@ func (g *Grid) CatchPokemon(x, y int, pokemonNumber string) (*models.Pokemon, er
 	return pokemon, nil
 }
 
-func (g *Grid) MovePlayer(player *models.Player, newX, newY int) error {
-	if !g.isValidPosition(newX, newY) {
-		return fmt.Errorf("invalid position: (%d,%d)", newX, newY)
-	}
-
-	oldPos := player.GetPosition()
-	oldCell, _ := g.GetCell(oldPos.X, oldPos.Y)
-	newCell, _ := g.GetCell(newX, newY)
-
-	// Lock cells theo thứ tự để tránh deadlock
-	if oldPos.Y < newY || (oldPos.Y == newY && oldPos.X < newX) {
-		oldCell.mu.Lock()
-		newCell.mu.Lock()
-	} else {
-		newCell.mu.Lock()
-		oldCell.mu.Lock()
+// placePokemon - Đặt Pokemon vào ô (x, y), tạo ô nếu cần
+func (g *Grid) placePokemon(x, y int, pokemon *models.Pokemon) {
+	cell, release, err := g.pinCell(x, y, true)
+	if err != nil {
+		return
 	}
-	defer oldCell.mu.Unlock()
-	defer newCell.mu.Unlock()
-
-	// Update cells
-	delete(oldCell.Players, player.GetID())
-	newCell.Players[player.GetID()] = player
-
-	return nil
+	cell.mu.Lock()
+	cell.Pokemon[pokemon.Number] = pokemon
+	cell.mu.Unlock()
+	release()
 }
@@ -0,0 +1,95 @@
+package tournament
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/clock"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/config"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/database"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/game/pokebat"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/random"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/testutil"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.RunInTempDir(m))
+}
+
+var testStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// onlinePlayers - PlayerLookup theo danh sách player đang online, đổi được trong lúc test
+type onlinePlayers struct {
+	players map[string]*models.Player
+	mu      sync.Mutex
+}
+
+func (o *onlinePlayers) lookup(playerID string) (*models.Player, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	player, online := o.players[playerID]
+	return player, online
+}
+
+func (o *onlinePlayers) set(players ...*models.Player) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.players = make(map[string]*models.Player)
+	for _, player := range players {
+		o.players[player.GetID()] = player
+	}
+}
+
+// newTestManager - Manager giải đấu và battle chạy theo đồng hồ giả, bắt đầu với thư mục giải trống
+func newTestManager(t *testing.T) (*Manager, *onlinePlayers, *clock.Fake) {
+	t.Helper()
+	if err := os.RemoveAll(constants.TournamentDir); err != nil {
+		t.Fatal(err)
+	}
+	clk := clock.NewFake(testStart)
+	battles := pokebat.NewManagerWithClock(clk)
+	t.Cleanup(battles.Cleanup)
+
+	online := &onlinePlayers{}
+	manager, err := NewManagerWithClock(battles, online.lookup, clk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(manager.Cleanup)
+	return manager, online, clk
+}
+
+// newTestEntrant - Player có team chuẩn đã chọn, sẵn sàng đăng ký giải
+func newTestEntrant(t *testing.T, clk clock.Clock, id string) *models.Player {
+	t.Helper()
+	pokedex, err := database.GetPokedex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := models.NewPlayerWithSources(id, config.Default(), clk, random.New(1))
+	t.Cleanup(func() { player.Cleanup() })
+
+	team := make([]string, 0, constants.MaxBattlePokemon)
+	for _, name := range []string{"Bulbasaur", "Charmander", "Squirtle"} {
+		entry, err := pokedex.Find(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pokemon, err := models.NewPokemon(entry.ToMap(), 10, constants.DefaultEV)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := player.AddPokemon(pokemon); err != nil {
+			t.Fatal(err)
+		}
+		team = append(team, pokemon.Number)
+	}
+	if err := player.SelectBattleTeam(team); err != nil {
+		t.Fatal(err)
+	}
+	return player
+}
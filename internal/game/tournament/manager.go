@@ -0,0 +1,502 @@
+package tournament
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/clock"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/game/pokebat"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// PlayerLookup - Tìm player đang online theo ID
+type PlayerLookup func(playerID string) (*models.Player, bool)
+
+// Manager - Quản lý các giải đấu, tự động bắt đầu vòng đấu và lưu kết quả
+type Manager struct {
+	battles     *pokebat.Manager
+	lookup      PlayerLookup
+	tournaments map[string]*Tournament
+	running     map[string]*pokebat.Battle // tournamentID/matchID -> battle đang diễn ra
+	nextID      uint64
+	stopped     bool
+	mu          sync.Mutex
+	clock       clock.Clock
+	ticker      clock.Ticker
+	done        chan struct{}
+}
+
+// NewManager - Tạo manager và khôi phục các giải đã lưu
+func NewManager(battles *pokebat.Manager, lookup PlayerLookup) (*Manager, error) {
+	return NewManagerWithClock(battles, lookup, clock.Real())
+}
+
+// NewManagerWithClock - Như NewManager, thời gian chờ và chu kỳ kiểm tra trận chạy theo clk
+func NewManagerWithClock(battles *pokebat.Manager, lookup PlayerLookup, clk clock.Clock) (*Manager, error) {
+	manager := &Manager{
+		clock:       clk,
+		battles:     battles,
+		lookup:      lookup,
+		tournaments: make(map[string]*Tournament),
+		running:     make(map[string]*pokebat.Battle),
+		done:        make(chan struct{}),
+	}
+	if err := manager.loadAll(); err != nil {
+		return nil, err
+	}
+
+	manager.startRoutine()
+	return manager, nil
+}
+
+// Create - Tạo giải mới ở trạng thái nhận đăng ký, mọi trận dùng chung một thể thức battle
+func (m *Manager) Create(creatorID, name string, format Format, battleFormat *pokebat.BattleFormat,
+	maxPlayers, swissRounds int) (Summary, error) {
+	if !validFormat(format) {
+		return Summary{}, fmt.Errorf("unknown tournament format: %s", format)
+	}
+	if name == "" {
+		return Summary{}, fmt.Errorf("tournament name is required")
+	}
+	if maxPlayers == 0 {
+		maxPlayers = constants.MaxTournamentPlayers
+	}
+	if maxPlayers < constants.MinTournamentPlayers || maxPlayers > constants.MaxTournamentPlayers {
+		return Summary{}, fmt.Errorf("max players must be between %d and %d",
+			constants.MinTournamentPlayers, constants.MaxTournamentPlayers)
+	}
+	if swissRounds < 0 || (format != FormatSwiss && swissRounds != 0) {
+		return Summary{}, fmt.Errorf("invalid number of swiss rounds")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	t := &Tournament{
+		ID:           fmt.Sprintf("tournament-%d", m.nextID),
+		Name:         name,
+		Format:       format,
+		BattleFormat: battleFormat.Name,
+		CreatorID:    creatorID,
+		State:        StateRegistration,
+		MaxPlayers:   maxPlayers,
+		SwissRounds:  swissRounds,
+		Entrants:     make([]*Entrant, 0),
+		Rounds:       make([]*Round, 0),
+		CreatedAt:    m.clock.Now(),
+	}
+	if err := m.save(t); err != nil {
+		return Summary{}, err
+	}
+	m.tournaments[t.ID] = t
+	return t.summary(), nil
+}
+
+// Register - Đăng ký player, khóa team hiện tại đã chọn bằng SelectBattleTeam
+func (m *Manager) Register(tournamentID string, player *models.Player) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, err := m.get(tournamentID)
+	if err != nil {
+		return err
+	}
+	format, err := pokebat.GetFormat(t.BattleFormat)
+	if err != nil {
+		return err
+	}
+	if err := pokebat.ValidateTeam(player, format); err != nil {
+		return err
+	}
+	if err := t.checkRegistration(); err != nil {
+		return err
+	}
+	if t.getEntrant(player.GetID()) != nil {
+		return fmt.Errorf(constants.ErrAlreadyRegistered)
+	}
+	if len(t.Entrants) >= t.MaxPlayers {
+		return fmt.Errorf(constants.ErrTournamentFull)
+	}
+
+	t.Entrants = append(t.Entrants, &Entrant{
+		PlayerID:     player.GetID(),
+		Team:         player.GetBattleTeam(),
+		Rating:       player.GetRating(),
+		RegisteredAt: m.clock.Now(),
+	})
+	return m.save(t)
+}
+
+// Unregister - Rút tên khỏi giải trước khi giải bắt đầu
+func (m *Manager) Unregister(tournamentID, playerID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, err := m.get(tournamentID)
+	if err != nil {
+		return err
+	}
+	if err := t.checkRegistration(); err != nil {
+		return err
+	}
+	for i, entrant := range t.Entrants {
+		if entrant.PlayerID == playerID {
+			t.Entrants = append(t.Entrants[:i], t.Entrants[i+1:]...)
+			return m.save(t)
+		}
+	}
+	return fmt.Errorf("player %s is not registered", playerID)
+}
+
+// Start - Người tạo giải đóng đăng ký, xếp hạt giống và bắt đầu vòng 1
+func (m *Manager) Start(tournamentID, playerID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, err := m.get(tournamentID)
+	if err != nil {
+		return err
+	}
+	if t.CreatorID != playerID {
+		return fmt.Errorf("only the creator can start the tournament")
+	}
+	if err := t.checkRegistration(); err != nil {
+		return err
+	}
+	if len(t.Entrants) < constants.MinTournamentPlayers {
+		return fmt.Errorf("tournament needs at least %d players", constants.MinTournamentPlayers)
+	}
+
+	now := m.clock.Now()
+	t.State = StateRunning
+	t.StartedAt = now
+	if t.Format == FormatSwiss && t.SwissRounds == 0 {
+		t.SwissRounds = defaultSwissRounds(len(t.Entrants))
+	}
+	t.seedEntrants()
+	t.placeInBracket()
+	m.advance(t, now)
+	return m.save(t)
+}
+
+// List - Danh sách các giải, giải mới nhất trước
+func (m *Manager) List() []Summary {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]Summary, 0, len(m.tournaments))
+	for _, t := range m.tournaments {
+		result = append(result, t.summary())
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return m.tournaments[result[i].ID].CreatedAt.After(m.tournaments[result[j].ID].CreatedAt)
+	})
+	return result
+}
+
+// Get - Lấy bản sao đầy đủ của giải (vòng đấu, trận, entrant)
+func (m *Manager) Get(tournamentID string) (*Tournament, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, err := m.get(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	return t.clone()
+}
+
+// Standings - Bảng xếp hạng hiện tại của giải
+func (m *Manager) Standings(tournamentID string) ([]Standing, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, err := m.get(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	return t.standings(), nil
+}
+
+// Cleanup - Dừng routine trước khi battle bị dừng, trạng thái giải đã được lưu sau mỗi thay đổi.
+// Gọi nhiều lần không lỗi.
+func (m *Manager) Cleanup() {
+	// Battle bị hủy khi tắt server không được tính kết quả - sẽ đấu lại khi khởi động
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stopped {
+		return
+	}
+	m.stopped = true
+	close(m.done)
+}
+
+func (m *Manager) get(tournamentID string) (*Tournament, error) {
+	t, exists := m.tournaments[tournamentID]
+	if !exists {
+		return nil, fmt.Errorf(constants.ErrTournamentNotFound)
+	}
+	return t, nil
+}
+
+// startRoutine - Định kỳ bắt đầu trận, thu kết quả và mở vòng mới
+func (m *Manager) startRoutine() {
+	m.ticker = m.clock.NewTicker(time.Duration(constants.TournamentInterval) * time.Second)
+
+	go func() {
+		for {
+			select {
+			case <-m.ticker.C():
+				m.update(m.clock.Now())
+			case <-m.done:
+				m.ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (m *Manager) update(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopped {
+		return
+	}
+	for _, t := range m.tournaments {
+		if t.State != StateRunning {
+			continue
+		}
+		if m.advance(t, now) {
+			if err := m.save(t); err != nil {
+				log.Printf("Failed to save tournament %s: %v", t.ID, err)
+			}
+		}
+	}
+}
+
+// advance - Cập nhật các trận của vòng hiện tại và mở vòng mới khi vòng cũ xong (cần giữ m.mu)
+func (m *Manager) advance(t *Tournament, now time.Time) bool {
+	changed := false
+	for {
+		round := t.currentRound()
+		if round != nil {
+			for _, match := range round.Matches {
+				if m.updateMatch(t, match, now) {
+					changed = true
+				}
+			}
+			if !roundFinished(round) {
+				return changed
+			}
+			if round.FinishedAt.IsZero() {
+				round.FinishedAt = now
+			}
+		}
+
+		// Vòng mới có thể hoàn tất ngay nếu chỉ toàn miễn đấu
+		changed = true
+		if t.nextRound(now) == nil {
+			m.finish(t, now)
+			return changed
+		}
+	}
+}
+
+func roundFinished(round *Round) bool {
+	for _, match := range round.Matches {
+		if match.State != MatchFinished {
+			return false
+		}
+	}
+	return true
+}
+
+// finish - Kết thúc giải và xác định nhà vô địch
+func (m *Manager) finish(t *Tournament, now time.Time) {
+	t.State = StateFinished
+	t.FinishedAt = now
+	if t.Format == FormatSwiss {
+		if standings := t.standings(); len(standings) > 0 {
+			t.WinnerID = standings[0].PlayerID
+		}
+		return
+	}
+	if alive := t.alive(); len(alive) == 1 {
+		t.WinnerID = alive[0].PlayerID
+	}
+}
+
+func matchKey(t *Tournament, match *Match) string {
+	return t.ID + "/" + match.ID
+}
+
+// updateMatch - Bắt đầu battle khi hai player có mặt, hoặc thu kết quả battle (cần giữ m.mu)
+func (m *Manager) updateMatch(t *Tournament, match *Match, now time.Time) bool {
+	switch match.State {
+	case MatchPending:
+		return m.startMatch(t, match, now)
+	case MatchRunning:
+		battle, exists := m.running[matchKey(t, match)]
+		if !exists {
+			// Server khởi động lại - battle cũ đã mất, đấu lại trận này
+			match.State = MatchPending
+			match.BattleID = ""
+			match.PendingSince = now
+			return true
+		}
+		if battle.GetState() != pokebat.BattleStateFinished {
+			return false
+		}
+		delete(m.running, matchKey(t, match))
+		m.resolveBattle(t, match, battle, now)
+		return true
+	}
+	return false
+}
+
+// startMatch - Tạo battle với team đã khóa, xử thua player vắng mặt quá thời gian chờ
+func (m *Manager) startMatch(t *Tournament, match *Match, now time.Time) bool {
+	format, err := pokebat.GetFormat(t.BattleFormat)
+	if err != nil {
+		// Thể thức không còn tồn tại (ví dụ đăng ký lúc chạy và server khởi động lại)
+		format = pokebat.FormatStandard
+	}
+	p1, ok1 := m.prepare(t.getEntrant(match.Player1), format)
+	p2, ok2 := m.prepare(t.getEntrant(match.Player2), format)
+
+	if ok1 && ok2 {
+		battle, err := m.battles.CreateBattle(p1, p2, format)
+		if err == nil {
+			match.State = MatchRunning
+			match.BattleID = battle.ID
+			m.running[matchKey(t, match)] = battle
+			return true
+		}
+		log.Printf("Failed to start tournament match %s/%s: %v", t.ID, match.ID, err)
+	}
+
+	if now.Sub(match.PendingSince) < time.Duration(constants.MatchShowUpGrace)*time.Second {
+		return false
+	}
+	switch {
+	case ok1:
+		t.applyResult(match, match.Player1, ResultNoShow, now)
+	case ok2:
+		t.applyResult(match, match.Player2, ResultNoShow, now)
+	default:
+		t.applyResult(match, "", ResultDoubleNoShow, now)
+	}
+	return true
+}
+
+// prepare - Player phải online, rảnh và team đã khóa vẫn còn hợp lệ
+func (m *Manager) prepare(entrant *Entrant, format *pokebat.BattleFormat) (*models.Player, bool) {
+	player, online := m.lookup(entrant.PlayerID)
+	if !online || player.IsInBattle() {
+		return nil, false
+	}
+	if err := player.SelectBattleTeam(entrant.Team); err != nil {
+		return nil, false
+	}
+	return player, pokebat.ValidateTeam(player, format) == nil
+}
+
+// resolveBattle - Chuyển kết quả battle thành kết quả trận
+func (m *Manager) resolveBattle(t *Tournament, match *Match, battle *pokebat.Battle, now time.Time) {
+	winnerID, reason := battle.GetResult()
+	if winnerID != "" {
+		t.applyResult(match, winnerID, string(reason), now)
+		return
+	}
+
+	// Battle hết hạn khi chưa bắt đầu - ai không sẵn sàng bị xử vắng mặt
+	if battle.Summary().Turn == 0 {
+		ready1 := battle.IsPlayerReady(match.Player1)
+		ready2 := battle.IsPlayerReady(match.Player2)
+		switch {
+		case ready1:
+			t.applyResult(match, match.Player1, ResultNoShow, now)
+		case ready2:
+			t.applyResult(match, match.Player2, ResultNoShow, now)
+		default:
+			t.applyResult(match, "", ResultDoubleNoShow, now)
+		}
+		return
+	}
+
+	if t.maxLosses() == 0 {
+		t.applyResult(match, "", ResultDraw, now)
+		return
+	}
+	// Loại trực tiếp không có hòa - hạt giống cao hơn đi tiếp
+	winner := match.Player1
+	if t.getEntrant(match.Player2).Seed < t.getEntrant(match.Player1).Seed {
+		winner = match.Player2
+	}
+	t.applyResult(match, winner, ResultSeedTiebreak, now)
+}
+
+// save - Lưu giải ra file JSON (temp file + rename)
+func (m *Manager) save(t *Tournament) error {
+	if err := os.MkdirAll(constants.TournamentDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(t, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal tournament: %v", err)
+	}
+
+	filename := filepath.Join(constants.TournamentDir, fmt.Sprintf("%s.json", t.ID))
+	tempFile := filename + ".tmp"
+	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write temporary file: %v", err)
+	}
+	if err := os.Rename(tempFile, filename); err != nil {
+		os.Remove(tempFile)
+		return fmt.Errorf("failed to save tournament: %v", err)
+	}
+	return nil
+}
+
+// loadAll - Đọc lại các giải đã lưu khi server khởi động
+func (m *Manager) loadAll() error {
+	files, err := os.ReadDir(constants.TournamentDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read tournament directory: %v", err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(constants.TournamentDir, file.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read tournament %s: %v", file.Name(), err)
+		}
+		var t Tournament
+		if err := json.Unmarshal(data, &t); err != nil {
+			return fmt.Errorf("failed to parse tournament %s: %v", file.Name(), err)
+		}
+		m.tournaments[t.ID] = &t
+
+		if id, err := strconv.ParseUint(strings.TrimPrefix(t.ID, "tournament-"), 10, 64); err == nil && id > m.nextID {
+			m.nextID = id
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package tournament
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/clock"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/game/pokebat"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+const showUpGrace = time.Duration(constants.MatchShowUpGrace) * time.Second
+
+// startTestTournament - Giải loại trực tiếp hai người "alice" và "bob" do alice tạo, đã bắt đầu vòng 1
+func startTestTournament(t *testing.T, manager *Manager, online *onlinePlayers, clk clock.Clock,
+	present ...string) (string, []*models.Player) {
+	t.Helper()
+	players := []*models.Player{newTestEntrant(t, clk, "alice"), newTestEntrant(t, clk, "bob")}
+	summary, err := manager.Create("alice", "Test Cup", FormatSingleElimination, pokebat.FormatStandard, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, player := range players {
+		if err := manager.Register(summary.ID, player); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var here []*models.Player
+	for _, player := range players {
+		for _, id := range present {
+			if player.GetID() == id {
+				here = append(here, player)
+			}
+		}
+	}
+	online.set(here...)
+	if err := manager.Start(summary.ID, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	return summary.ID, players
+}
+
+func firstMatch(t *testing.T, manager *Manager, tournamentID string) (*Tournament, *Match) {
+	t.Helper()
+	tournament, err := manager.Get(tournamentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tournament.Rounds) == 0 || len(tournament.Rounds[0].Matches) == 0 {
+		t.Fatal("tournament has no match")
+	}
+	return tournament, tournament.Rounds[0].Matches[0]
+}
+
+func TestNoShowAfterGrace(t *testing.T) {
+	tests := []struct {
+		name    string
+		present []string
+		winner  string
+		result  string
+	}{
+		{"no show", []string{"alice"}, "alice", ResultNoShow},
+		{"double no show", nil, "", ResultDoubleNoShow},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			manager, online, clk := newTestManager(t)
+			id, _ := startTestTournament(t, manager, online, clk, test.present...)
+
+			clk.Advance(showUpGrace - time.Second)
+			manager.update(clk.Now())
+			if _, match := firstMatch(t, manager, id); match.State != MatchPending {
+				t.Fatalf("match is %s before the show-up grace ended, want pending", match.State)
+			}
+
+			clk.Advance(time.Second)
+			manager.update(clk.Now())
+			tournament, match := firstMatch(t, manager, id)
+			if match.State != MatchFinished || match.Result != test.result || match.WinnerID != test.winner {
+				t.Fatalf("match = %s/%s won by %q, want finished/%s won by %q",
+					match.State, match.Result, match.WinnerID, test.result, test.winner)
+			}
+			if tournament.State != StateFinished || tournament.WinnerID != test.winner {
+				t.Errorf("tournament = %s won by %q, want finished won by %q",
+					tournament.State, tournament.WinnerID, test.winner)
+			}
+			for _, entrant := range tournament.Entrants {
+				if entrant.PlayerID != test.winner && !entrant.Dropped {
+					t.Errorf("absent player %s was not dropped", entrant.PlayerID)
+				}
+			}
+		})
+	}
+}
+
+// TestReloadResetsRunningMatch - Battle mất khi server khởi động lại nên trận đang đấu được đấu lại
+func TestReloadResetsRunningMatch(t *testing.T) {
+	manager, online, clk := newTestManager(t)
+	id, _ := startTestTournament(t, manager, online, clk, "alice", "bob")
+	if _, match := firstMatch(t, manager, id); match.State != MatchRunning || match.BattleID == "" {
+		t.Fatalf("match = %s with battle %q, want running", match.State, match.BattleID)
+	}
+	manager.Cleanup()
+
+	clk.Advance(time.Minute)
+	online.set()
+	reloaded, err := NewManagerWithClock(pokebat.NewManagerWithClock(clk), online.lookup, clk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Cleanup()
+	if _, match := firstMatch(t, reloaded, id); match.State != MatchRunning {
+		t.Fatalf("reloaded match is %s, want the saved running state", match.State)
+	}
+
+	reloaded.update(clk.Now())
+	_, match := firstMatch(t, reloaded, id)
+	if match.State != MatchPending || match.BattleID != "" || !match.PendingSince.Equal(clk.Now()) {
+		t.Errorf("match after restart = %s with battle %q pending since %v, want pending since %v",
+			match.State, match.BattleID, match.PendingSince, clk.Now())
+	}
+}
+
+func TestManagerCleanupTwice(t *testing.T) {
+	manager, _, _ := newTestManager(t)
+	manager.Cleanup()
+	manager.Cleanup()
+}
@@ -0,0 +1,256 @@
+package tournament
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// pairing - Một cặp đấu của vòng mới (player2 rỗng là miễn đấu)
+type pairing struct {
+	bracket string
+	player1 string
+	player2 string
+}
+
+// nextRound - Tạo vòng tiếp theo, trả về nil khi giải đã có kết quả
+func (t *Tournament) nextRound(now time.Time) *Round {
+	var pairings []pairing
+	switch t.Format {
+	case FormatSwiss:
+		pairings = t.pairSwiss()
+	case FormatSingleElimination:
+		pairings = t.pairSingleElimination()
+	case FormatDoubleElimination:
+		pairings = t.pairDoubleElimination()
+	}
+	if len(pairings) == 0 {
+		return nil
+	}
+
+	round := &Round{Number: len(t.Rounds) + 1, StartedAt: now}
+	for i, pair := range pairings {
+		match := &Match{
+			ID:           fmt.Sprintf("r%d-m%d", round.Number, i+1),
+			Round:        round.Number,
+			Bracket:      pair.bracket,
+			Player1:      pair.player1,
+			Player2:      pair.player2,
+			State:        MatchPending,
+			PendingSince: now,
+		}
+		round.Matches = append(round.Matches, match)
+		if pair.player2 == "" {
+			t.applyResult(match, pair.player1, ResultBye, now)
+		}
+	}
+	t.Rounds = append(t.Rounds, round)
+	return round
+}
+
+// alive - Các entrant còn được xếp cặp
+func (t *Tournament) alive() []*Entrant {
+	result := make([]*Entrant, 0, len(t.Entrants))
+	for _, entrant := range t.Entrants {
+		if t.isAlive(entrant) {
+			result = append(result, entrant)
+		}
+	}
+	return result
+}
+
+// pairSwiss - Xếp cặp theo điểm, tránh gặp lại đối thủ cũ khi có thể
+func (t *Tournament) pairSwiss() []pairing {
+	players := t.alive()
+	if len(t.Rounds) >= t.SwissRounds || len(players) < 2 {
+		return nil
+	}
+
+	sort.SliceStable(players, func(i, j int) bool {
+		if players[i].Points != players[j].Points {
+			return players[i].Points > players[j].Points
+		}
+		return players[i].Seed < players[j].Seed
+	})
+
+	pairings := make([]pairing, 0, len(players)/2+1)
+	if len(players)%2 == 1 {
+		// Miễn đấu cho player thứ hạng thấp nhất chưa từng được miễn
+		byeIndex := len(players) - 1
+		for i := len(players) - 1; i >= 0; i-- {
+			if players[i].Byes == 0 {
+				byeIndex = i
+				break
+			}
+		}
+		pairings = append(pairings, pairing{bracket: BracketMain, player1: players[byeIndex].PlayerID})
+		players = append(players[:byeIndex:byeIndex], players[byeIndex+1:]...)
+	}
+
+	opponents := make([]int, len(players))
+	budget := swissSearchBudget
+	if !pairWithoutRematch(players, opponents, make([]bool, len(players)), &budget) {
+		// Không tránh được gặp lại - ghép player kế tiếp theo thứ hạng
+		for i := 0; i < len(players); i += 2 {
+			opponents[i], opponents[i+1] = i+1, i
+		}
+	}
+	for i, opponent := range opponents {
+		if opponent > i {
+			pairings = append(pairings, pairing{bracket: BracketMain, player1: players[i].PlayerID, player2: players[opponent].PlayerID})
+		}
+	}
+	return pairings
+}
+
+// swissSearchBudget - Giới hạn số bước thử khi tìm cách ghép không gặp lại
+const swissSearchBudget = 10000
+
+// pairWithoutRematch - Quay lui tìm cách ghép mà không ai gặp lại đối thủ cũ,
+// ưu tiên đối thủ gần thứ hạng nhất. opponents[i] lưu vị trí đối thủ của player i
+func pairWithoutRematch(players []*Entrant, opponents []int, paired []bool, budget *int) bool {
+	first := -1
+	for i := range players {
+		if !paired[i] {
+			first = i
+			break
+		}
+	}
+	if first == -1 {
+		return true
+	}
+
+	paired[first] = true
+	for j := first + 1; j < len(players); j++ {
+		if paired[j] || hasPlayed(players[first], players[j].PlayerID) {
+			continue
+		}
+		if *budget--; *budget < 0 {
+			break
+		}
+		paired[j] = true
+		if pairWithoutRematch(players, opponents, paired, budget) {
+			opponents[first], opponents[j] = j, first
+			return true
+		}
+		paired[j] = false
+	}
+	paired[first] = false
+	return false
+}
+
+func hasPlayed(entrant *Entrant, opponentID string) bool {
+	for _, id := range entrant.Opponents {
+		if id == opponentID {
+			return true
+		}
+	}
+	return false
+}
+
+// pairSingleElimination - Xếp cặp theo cây loại trực tiếp
+func (t *Tournament) pairSingleElimination() []pairing {
+	players := t.alive()
+	if len(players) < 2 {
+		return nil
+	}
+	return pairBracket(players, BracketMain)
+}
+
+// pairDoubleElimination - Nhánh thắng theo cây, nhánh thua theo thứ tự rơi xuống,
+// chung kết khi mỗi nhánh còn một player (thua ở chung kết sẽ đấu lại)
+func (t *Tournament) pairDoubleElimination() []pairing {
+	upper := make([]*Entrant, 0)
+	lower := make([]*Entrant, 0)
+	for _, entrant := range t.alive() {
+		if entrant.Losses == 0 {
+			upper = append(upper, entrant)
+		} else {
+			lower = append(lower, entrant)
+		}
+	}
+
+	switch {
+	case len(upper)+len(lower) < 2:
+		return nil
+	case len(upper) == 1 && len(lower) == 1:
+		return []pairing{{bracket: BracketFinal, player1: upper[0].PlayerID, player2: lower[0].PlayerID}}
+	case len(upper) == 0 && len(lower) == 2:
+		return []pairing{{bracket: BracketFinal, player1: lower[0].PlayerID, player2: lower[1].PlayerID}}
+	}
+
+	pairings := make([]pairing, 0)
+	if len(upper) >= 2 {
+		pairings = append(pairings, pairBracket(upper, BracketUpper)...)
+	}
+	if len(lower) >= 2 {
+		sort.Slice(lower, func(i, j int) bool { return lower[i].LowerPosition < lower[j].LowerPosition })
+		if len(lower)%2 == 1 {
+			// Miễn đấu cho player ít lần được miễn nhất, ưu tiên người ở nhánh thua lâu nhất
+			byeIndex := 0
+			for i, entrant := range lower {
+				if entrant.Byes < lower[byeIndex].Byes {
+					byeIndex = i
+				}
+			}
+			pairings = append(pairings, pairing{bracket: BracketLower, player1: lower[byeIndex].PlayerID})
+			lower = append(lower[:byeIndex:byeIndex], lower[byeIndex+1:]...)
+		}
+		for i := 0; i+1 < len(lower); i += 2 {
+			pairings = append(pairings, pairing{bracket: BracketLower, player1: lower[i].PlayerID, player2: lower[i+1].PlayerID})
+		}
+	}
+	return pairings
+}
+
+// pairBracket - Hai player cùng nhánh con (Position/2) gặp nhau, player lẻ được miễn đấu
+func pairBracket(players []*Entrant, bracket string) []pairing {
+	groups := make(map[int][]*Entrant)
+	keys := make([]int, 0)
+	for _, entrant := range players {
+		key := entrant.Position / 2
+		if _, exists := groups[key]; !exists {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], entrant)
+		entrant.Position = key
+	}
+	sort.Ints(keys)
+
+	pairings := make([]pairing, 0, len(keys))
+	for _, key := range keys {
+		group := groups[key]
+		pair := pairing{bracket: bracket, player1: group[0].PlayerID}
+		if len(group) > 1 {
+			pair.player2 = group[1].PlayerID
+		}
+		pairings = append(pairings, pair)
+	}
+	return pairings
+}
+
+// placeInBracket - Đặt hạt giống vào cây chuẩn để hạt giống cao nhận miễn đấu và gặp nhau muộn nhất
+func (t *Tournament) placeInBracket() {
+	size := 1
+	for size < len(t.Entrants) {
+		size *= 2
+	}
+
+	// Thứ tự hạt giống theo vị trí: 1,8,4,5,2,7,3,6 với cây 8
+	order := []int{1}
+	for len(order) < size {
+		next := make([]int, 0, len(order)*2)
+		for _, seed := range order {
+			next = append(next, seed, len(order)*2+1-seed)
+		}
+		order = next
+	}
+
+	slots := make(map[int]int)
+	for position, seed := range order {
+		slots[seed] = position
+	}
+	for _, entrant := range t.Entrants {
+		entrant.Position = slots[entrant.Seed]
+	}
+}
@@ -0,0 +1,135 @@
+package tournament
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// newPairingTournament - Giải chưa bắt đầu với n entrant "s1".."sn", rating giảm dần nên hạt giống i là "si"
+func newPairingTournament(format Format, n int) *Tournament {
+	t := &Tournament{ID: "pairing-test", Format: format, State: StateRunning}
+	for i := 1; i <= n; i++ {
+		t.Entrants = append(t.Entrants, &Entrant{PlayerID: fmt.Sprintf("s%d", i), Rating: 2000 - i})
+	}
+	if format == FormatSwiss {
+		t.SwissRounds = defaultSwissRounds(n)
+	}
+	t.seedEntrants()
+	t.placeInBracket()
+	return t
+}
+
+// roundPairs - Các cặp đấu của vòng, "sX-" là miễn đấu
+func roundPairs(round *Round) []string {
+	pairs := make([]string, len(round.Matches))
+	for i, match := range round.Matches {
+		pairs[i] = match.Player1 + "-" + match.Player2
+	}
+	return pairs
+}
+
+// finishRound - Player1 thắng mọi trận chưa có kết quả của vòng
+func finishRound(t *Tournament, round *Round) {
+	for _, match := range round.Matches {
+		if match.State != MatchFinished {
+			t.applyResult(match, match.Player1, "ko", testStart)
+		}
+	}
+}
+
+func TestSingleEliminationPairing(t *testing.T) {
+	tests := []struct {
+		players int
+		rounds  [][]string
+	}{
+		{8, [][]string{
+			{"s1-s8", "s4-s5", "s2-s7", "s3-s6"},
+			{"s1-s4", "s2-s3"},
+			{"s1-s2"},
+		}},
+		// Hạt giống cao nhận miễn đấu ở vòng đầu
+		{5, [][]string{
+			{"s1-", "s4-s5", "s2-", "s3-"},
+			{"s1-s4", "s2-s3"},
+			{"s1-s2"},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d players", test.players), func(t *testing.T) {
+			tournament := newPairingTournament(FormatSingleElimination, test.players)
+			for i, want := range test.rounds {
+				round := tournament.nextRound(testStart)
+				if round == nil {
+					t.Fatalf("round %d was not created", i+1)
+				}
+				if got := roundPairs(round); !reflect.DeepEqual(got, want) {
+					t.Fatalf("round %d pairs = %v, want %v", i+1, got, want)
+				}
+				finishRound(tournament, round)
+			}
+			if round := tournament.nextRound(testStart); round != nil {
+				t.Errorf("extra round %v after the final", roundPairs(round))
+			}
+		})
+	}
+}
+
+func TestDoubleEliminationPairing(t *testing.T) {
+	tournament := newPairingTournament(FormatDoubleElimination, 4)
+	want := [][]string{
+		{"s1-s4", "s2-s3"},
+		{"s1-s2", "s4-s3"}, // Nhánh thắng và nhánh thua
+		{"s4-s2"},          // Chung kết nhánh thua với người vừa thua nhánh thắng
+		{"s1-s4"},          // Chung kết tổng
+	}
+	for i, pairs := range want {
+		round := tournament.nextRound(testStart)
+		if round == nil {
+			t.Fatalf("round %d was not created", i+1)
+		}
+		if got := roundPairs(round); !reflect.DeepEqual(got, pairs) {
+			t.Fatalf("round %d pairs = %v, want %v", i+1, got, pairs)
+		}
+		finishRound(tournament, round)
+	}
+	if final := tournament.Rounds[3].Matches[0]; final.Bracket != BracketFinal {
+		t.Errorf("last match is in bracket %s, want %s", final.Bracket, BracketFinal)
+	}
+}
+
+func TestSwissPairing(t *testing.T) {
+	t.Run("pairs by points without rematches", func(t *testing.T) {
+		tournament := newPairingTournament(FormatSwiss, 4)
+		first := tournament.nextRound(testStart)
+		if got, want := roundPairs(first), []string{"s1-s2", "s3-s4"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("round 1 pairs = %v, want %v", got, want)
+		}
+		finishRound(tournament, first)
+
+		// s1, s3 cùng 1 điểm gặp nhau, s2, s4 cùng 0 điểm gặp nhau
+		second := tournament.nextRound(testStart)
+		if got, want := roundPairs(second), []string{"s1-s3", "s2-s4"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("round 2 pairs = %v, want %v", got, want)
+		}
+		finishRound(tournament, second)
+		if round := tournament.nextRound(testStart); round != nil {
+			t.Errorf("extra round %v after %d swiss rounds", roundPairs(round), tournament.SwissRounds)
+		}
+	})
+
+	t.Run("bye goes to the lowest player without one", func(t *testing.T) {
+		tournament := newPairingTournament(FormatSwiss, 3)
+		first := tournament.nextRound(testStart)
+		if got, want := roundPairs(first), []string{"s3-", "s1-s2"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("round 1 pairs = %v, want %v", got, want)
+		}
+		finishRound(tournament, first)
+
+		// s2 thấp điểm nhất và chưa được miễn; s1 và s3 chưa gặp nhau
+		second := tournament.nextRound(testStart)
+		if got, want := roundPairs(second), []string{"s2-", "s1-s3"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("round 2 pairs = %v, want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,360 @@
+package tournament
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+)
+
+// Format - Thể thức thi đấu của giải
+type Format string
+
+const (
+	FormatSingleElimination Format = "single_elimination"
+	FormatDoubleElimination Format = "double_elimination"
+	FormatSwiss             Format = "swiss"
+)
+
+// State - Trạng thái của giải
+type State string
+
+const (
+	StateRegistration State = "registration"
+	StateRunning      State = "running"
+	StateFinished     State = "finished"
+)
+
+// MatchState - Trạng thái của một trận trong giải
+type MatchState string
+
+const (
+	MatchPending  MatchState = "pending"  // Chờ hai player có mặt
+	MatchRunning  MatchState = "running"  // Battle đang diễn ra
+	MatchFinished MatchState = "finished" // Đã có kết quả
+)
+
+// Kết quả trận ngoài kết quả của battle
+const (
+	ResultBye          = "bye"
+	ResultNoShow       = "no_show"
+	ResultDoubleNoShow = "double_no_show"
+	ResultDraw         = "draw"
+	ResultSeedTiebreak = "seed_tiebreak" // Hòa ở thể thức loại trực tiếp, hạt giống cao hơn đi tiếp
+)
+
+// Nhánh đấu của trận
+const (
+	BracketMain  = "main"
+	BracketUpper = "upper"
+	BracketLower = "lower"
+	BracketFinal = "final"
+)
+
+// Entrant - Một player đã đăng ký giải, team được khóa khi đăng ký
+type Entrant struct {
+	PlayerID        string    `json:"player_id"`
+	Team            []string  `json:"team"`
+	Rating          int       `json:"rating"`
+	Seed            int       `json:"seed"`
+	RegisteredAt    time.Time `json:"registered_at"`
+	Wins            int       `json:"wins"`
+	Losses          int       `json:"losses"`
+	Draws           int       `json:"draws"`
+	Byes            int       `json:"byes"`
+	Points          float64   `json:"points"`
+	Opponents       []string  `json:"opponents,omitempty"`
+	Position        int       `json:"position"`       // Vị trí trong cây nhánh thắng
+	LowerPosition   int       `json:"lower_position"` // Thứ tự trong nhánh thua (loại kép)
+	Dropped         bool      `json:"dropped"`        // Bị loại do vắng mặt
+	EliminatedRound int       `json:"eliminated_round,omitempty"`
+}
+
+// Match - Một trận trong giải
+type Match struct {
+	ID           string     `json:"id"`
+	Round        int        `json:"round"`
+	Bracket      string     `json:"bracket"`
+	Player1      string     `json:"player1"`
+	Player2      string     `json:"player2,omitempty"` // Rỗng nếu Player1 được miễn đấu
+	State        MatchState `json:"state"`
+	BattleID     string     `json:"battle_id,omitempty"`
+	WinnerID     string     `json:"winner_id,omitempty"`
+	Result       string     `json:"result,omitempty"`
+	PendingSince time.Time  `json:"pending_since"`
+	FinishedAt   time.Time  `json:"finished_at,omitempty"`
+}
+
+// Round - Một vòng đấu
+type Round struct {
+	Number     int       `json:"number"`
+	Matches    []*Match  `json:"matches"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at,omitempty"`
+}
+
+// Tournament - Dữ liệu một giải đấu, được lưu ra file sau mỗi thay đổi
+type Tournament struct {
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Format        Format     `json:"format"`
+	BattleFormat  string     `json:"battle_format"`
+	CreatorID     string     `json:"creator_id"`
+	State         State      `json:"state"`
+	MaxPlayers    int        `json:"max_players"`
+	SwissRounds   int        `json:"swiss_rounds,omitempty"`
+	Entrants      []*Entrant `json:"entrants"`
+	Rounds        []*Round   `json:"rounds"`
+	WinnerID      string     `json:"winner_id,omitempty"`
+	NextLowerSlot int        `json:"next_lower_slot"`
+	CreatedAt     time.Time  `json:"created_at"`
+	StartedAt     time.Time  `json:"started_at,omitempty"`
+	FinishedAt    time.Time  `json:"finished_at,omitempty"`
+}
+
+// Summary - Thông tin tóm tắt của giải
+type Summary struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Format       Format `json:"format"`
+	BattleFormat string `json:"battle_format"`
+	State        State  `json:"state"`
+	Players      int    `json:"players"`
+	MaxPlayers   int    `json:"max_players"`
+	Round        int    `json:"round"`
+	WinnerID     string `json:"winner_id,omitempty"`
+}
+
+// Standing - Một dòng trên bảng xếp hạng của giải
+type Standing struct {
+	Rank       int     `json:"rank"`
+	PlayerID   string  `json:"player_id"`
+	Seed       int     `json:"seed"`
+	Wins       int     `json:"wins"`
+	Losses     int     `json:"losses"`
+	Draws      int     `json:"draws"`
+	Points     float64 `json:"points"`
+	Buchholz   float64 `json:"buchholz,omitempty"`
+	Eliminated bool    `json:"eliminated"`
+}
+
+func validFormat(format Format) bool {
+	switch format {
+	case FormatSingleElimination, FormatDoubleElimination, FormatSwiss:
+		return true
+	}
+	return false
+}
+
+// getEntrant - Tìm entrant theo player ID
+func (t *Tournament) getEntrant(playerID string) *Entrant {
+	for _, entrant := range t.Entrants {
+		if entrant.PlayerID == playerID {
+			return entrant
+		}
+	}
+	return nil
+}
+
+// currentRound - Vòng đang diễn ra (nil nếu chưa bắt đầu)
+func (t *Tournament) currentRound() *Round {
+	if len(t.Rounds) == 0 {
+		return nil
+	}
+	return t.Rounds[len(t.Rounds)-1]
+}
+
+// maxLosses - Số trận thua trước khi bị loại (0 = không loại, Swiss)
+func (t *Tournament) maxLosses() int {
+	switch t.Format {
+	case FormatSingleElimination:
+		return 1
+	case FormatDoubleElimination:
+		return 2
+	}
+	return 0
+}
+
+// isAlive - Entrant còn được xếp cặp ở vòng sau
+func (t *Tournament) isAlive(entrant *Entrant) bool {
+	if entrant.Dropped {
+		return false
+	}
+	return t.maxLosses() == 0 || entrant.Losses < t.maxLosses()
+}
+
+// summary - Thông tin tóm tắt
+func (t *Tournament) summary() Summary {
+	return Summary{
+		ID:           t.ID,
+		Name:         t.Name,
+		Format:       t.Format,
+		BattleFormat: t.BattleFormat,
+		State:        t.State,
+		Players:      len(t.Entrants),
+		MaxPlayers:   t.MaxPlayers,
+		Round:        len(t.Rounds),
+		WinnerID:     t.WinnerID,
+	}
+}
+
+// clone - Bản sao sâu để trả cho bên ngoài mà không cần giữ lock
+func (t *Tournament) clone() (*Tournament, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy tournament: %v", err)
+	}
+	var copyTournament Tournament
+	if err := json.Unmarshal(data, &copyTournament); err != nil {
+		return nil, fmt.Errorf("failed to copy tournament: %v", err)
+	}
+	return &copyTournament, nil
+}
+
+// seedEntrants - Xếp hạt giống theo rating, cùng rating thì ai đăng ký trước xếp trên
+func (t *Tournament) seedEntrants() {
+	sort.SliceStable(t.Entrants, func(i, j int) bool {
+		if t.Entrants[i].Rating != t.Entrants[j].Rating {
+			return t.Entrants[i].Rating > t.Entrants[j].Rating
+		}
+		return t.Entrants[i].RegisteredAt.Before(t.Entrants[j].RegisteredAt)
+	})
+	for i, entrant := range t.Entrants {
+		entrant.Seed = i + 1
+	}
+}
+
+// applyResult - Ghi kết quả trận và cập nhật thành tích của hai player
+func (t *Tournament) applyResult(match *Match, winnerID, result string, now time.Time) {
+	match.State = MatchFinished
+	match.WinnerID = winnerID
+	match.Result = result
+	match.FinishedAt = now
+
+	p1 := t.getEntrant(match.Player1)
+	if match.Player2 == "" {
+		p1.Byes++
+		p1.Points++
+		return
+	}
+	p2 := t.getEntrant(match.Player2)
+	p1.Opponents = append(p1.Opponents, p2.PlayerID)
+	p2.Opponents = append(p2.Opponents, p1.PlayerID)
+
+	switch {
+	case result == ResultDoubleNoShow:
+		p1.Losses++
+		p2.Losses++
+		p1.Dropped = true
+		p2.Dropped = true
+	case winnerID == "":
+		p1.Draws++
+		p2.Draws++
+		p1.Points += 0.5
+		p2.Points += 0.5
+	default:
+		winner, loser := p1, p2
+		if winnerID == p2.PlayerID {
+			winner, loser = p2, p1
+		}
+		winner.Wins++
+		winner.Points++
+		loser.Losses++
+		if result == ResultNoShow {
+			loser.Dropped = true
+		}
+	}
+
+	for _, entrant := range []*Entrant{p1, p2} {
+		if !t.isAlive(entrant) && entrant.EliminatedRound == 0 {
+			entrant.EliminatedRound = match.Round
+		}
+		if t.Format == FormatDoubleElimination && entrant.Losses == 1 && entrant.LowerPosition == 0 {
+			// Rơi xuống nhánh thua, xếp sau các player đã ở nhánh thua
+			t.NextLowerSlot++
+			entrant.LowerPosition = t.NextLowerSlot
+		}
+	}
+}
+
+// standings - Bảng xếp hạng hiện tại của giải
+func (t *Tournament) standings() []Standing {
+	points := make(map[string]float64)
+	for _, entrant := range t.Entrants {
+		points[entrant.PlayerID] = entrant.Points
+	}
+
+	result := make([]Standing, 0, len(t.Entrants))
+	eliminatedRound := make(map[string]int)
+	for _, entrant := range t.Entrants {
+		standing := Standing{
+			PlayerID:   entrant.PlayerID,
+			Seed:       entrant.Seed,
+			Wins:       entrant.Wins,
+			Losses:     entrant.Losses,
+			Draws:      entrant.Draws,
+			Points:     entrant.Points,
+			Eliminated: t.maxLosses() > 0 && !t.isAlive(entrant),
+		}
+		// Buchholz - tổng điểm của các đối thủ đã gặp
+		for _, opponent := range entrant.Opponents {
+			standing.Buchholz += points[opponent]
+		}
+		eliminatedRound[entrant.PlayerID] = entrant.EliminatedRound
+		result = append(result, standing)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if t.Format == FormatSwiss {
+			if a.Points != b.Points {
+				return a.Points > b.Points
+			}
+			if a.Buchholz != b.Buchholz {
+				return a.Buchholz > b.Buchholz
+			}
+		} else {
+			if a.PlayerID == t.WinnerID || b.PlayerID == t.WinnerID {
+				return a.PlayerID == t.WinnerID
+			}
+			if a.Eliminated != b.Eliminated {
+				return !a.Eliminated
+			}
+			// Bị loại càng muộn xếp càng cao
+			if eliminatedRound[a.PlayerID] != eliminatedRound[b.PlayerID] {
+				return eliminatedRound[a.PlayerID] > eliminatedRound[b.PlayerID]
+			}
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		return a.Seed < b.Seed
+	})
+
+	for i := range result {
+		result[i].Rank = i + 1
+	}
+	return result
+}
+
+// defaultSwissRounds - Số vòng Swiss mặc định đủ để tìm ra người thắng duy nhất
+func defaultSwissRounds(players int) int {
+	rounds := 0
+	for size := 1; size < players; size *= 2 {
+		rounds++
+	}
+	if rounds == 0 {
+		rounds = 1
+	}
+	return rounds
+}
+
+// checkRegistration - Kiểm tra giải còn nhận đăng ký
+func (t *Tournament) checkRegistration() error {
+	if t.State != StateRegistration {
+		return fmt.Errorf(constants.ErrTournamentStarted)
+	}
+	return nil
+}
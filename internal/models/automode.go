@@ -0,0 +1,83 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+)
+
+// AutoMode - Trạng thái tự động di chuyển của player
+type AutoMode struct {
+	EndTime  time.Time
+	Strategy string
+	Steps    int
+}
+
+// IsActive - Chế độ tự động còn hiệu lực tại thời điểm now
+func (a AutoMode) IsActive(now time.Time) bool {
+	return a.Strategy != "" && now.Before(a.EndTime)
+}
+
+// ValidAutoStrategy - Kiểm tra tên chiến lược tự động di chuyển
+func ValidAutoStrategy(strategy string) bool {
+	switch strategy {
+	case constants.AutoStrategyRandom, constants.AutoStrategySweep, constants.AutoStrategyNearest:
+		return true
+	}
+	return false
+}
+
+// StartAutoMode - Bật chế độ tự động di chuyển trong duration với chiến lược strategy
+func (p *Player) StartAutoMode(duration time.Duration, strategy string) error {
+	if duration <= 0 || duration > time.Duration(constants.MaxAutoModeDuration)*time.Second {
+		return fmt.Errorf("invalid auto mode duration: %v", duration)
+	}
+	if !ValidAutoStrategy(strategy) {
+		return fmt.Errorf("unknown auto mode strategy: %s", strategy)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.data.AutoModeEndTime = p.clock.Now().Add(duration)
+	p.data.AutoStrategy = strategy
+	p.data.AutoSteps = 0
+	return p.saveToFile()
+}
+
+// StopAutoMode - Tắt chế độ tự động di chuyển
+func (p *Player) StopAutoMode() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.data.AutoModeEndTime = time.Time{}
+	p.data.AutoStrategy = ""
+	p.data.AutoSteps = 0
+	return p.saveToFile()
+}
+
+// GetAutoMode - Lấy trạng thái tự động di chuyển
+func (p *Player) GetAutoMode() AutoMode {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return AutoMode{
+		EndTime:  p.data.AutoModeEndTime,
+		Strategy: p.data.AutoStrategy,
+		Steps:    p.data.AutoSteps,
+	}
+}
+
+// CountAutoStep - Ghi nhận một bước tự động (được lưu cùng vị trí ở lần lưu kế tiếp)
+func (p *Player) CountAutoStep() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.data.AutoSteps++
+}
+
+// IsInventoryFull - Inventory đã đạt giới hạn
+func (p *Player) IsInventoryFull() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.data.PokemonList) >= p.maxInventory
+}
@@ -0,0 +1,278 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+)
+
+// MatchOutcome - Kết quả của một trận đấu với một player
+type MatchOutcome string
+
+const (
+	MatchWin  MatchOutcome = "win"
+	MatchLoss MatchOutcome = "loss"
+	MatchDraw MatchOutcome = "draw"
+)
+
+// MatchRecord - Một dòng trong lịch sử đấu của player
+type MatchRecord struct {
+	BattleID     string       `json:"battle_id"`
+	OpponentID   string       `json:"opponent_id"`
+	Outcome      MatchOutcome `json:"outcome"`
+	EndReason    string       `json:"end_reason"`
+	Rated        bool         `json:"rated"`
+	RatingBefore int          `json:"rating_before"`
+	RatingAfter  int          `json:"rating_after"`
+	Turns        int          `json:"turns"`
+	EndTime      time.Time    `json:"end_time"`
+}
+
+// BattleRecord - Rating và thành tích đấu của player; các trận với NPC hoặc Pokemon hoang dã
+// không tính rating và được đếm riêng ở các trường Unrated
+type BattleRecord struct {
+	Rating          int           `json:"rating"`
+	Wins            int           `json:"wins"`
+	Losses          int           `json:"losses"`
+	Draws           int           `json:"draws"`
+	KOWins          int           `json:"ko_wins"`
+	KOLosses        int           `json:"ko_losses"`
+	SurrenderWins   int           `json:"surrender_wins"`   // Đối thủ đầu hàng
+	SurrenderLosses int           `json:"surrender_losses"` // Player đầu hàng
+	UnratedWins     int           `json:"unrated_wins"`
+	UnratedLosses   int           `json:"unrated_losses"`
+	UnratedDraws    int           `json:"unrated_draws"`
+	Matches         []MatchRecord `json:"matches,omitempty"`
+}
+
+// LeaderboardEntry - Một dòng trên bảng xếp hạng
+type LeaderboardEntry struct {
+	Rank     int    `json:"rank"`
+	PlayerID string `json:"player_id"`
+	Rating   int    `json:"rating"`
+	Wins     int    `json:"wins"`
+	Losses   int    `json:"losses"`
+	Draws    int    `json:"draws"`
+}
+
+// newBattleRecord - Thành tích ban đầu của player mới
+func newBattleRecord() BattleRecord {
+	return BattleRecord{Rating: constants.InitialRating}
+}
+
+// Games - Tổng số trận xếp hạng đã đấu
+func (r *BattleRecord) Games() int {
+	return r.Wins + r.Losses + r.Draws
+}
+
+// UnratedGames - Tổng số trận không xếp hạng đã đấu
+func (r *BattleRecord) UnratedGames() int {
+	return r.UnratedWins + r.UnratedLosses + r.UnratedDraws
+}
+
+// add - Cập nhật thống kê và lịch sử theo kết quả trận đấu
+func (r *BattleRecord) add(match MatchRecord) {
+	r.Matches = append(r.Matches, match)
+	if len(r.Matches) > constants.MaxMatchHistory {
+		r.Matches = r.Matches[len(r.Matches)-constants.MaxMatchHistory:]
+	}
+
+	if !match.Rated {
+		switch match.Outcome {
+		case MatchWin:
+			r.UnratedWins++
+		case MatchLoss:
+			r.UnratedLosses++
+		case MatchDraw:
+			r.UnratedDraws++
+		}
+		return
+	}
+
+	switch match.Outcome {
+	case MatchWin:
+		r.Wins++
+		switch match.EndReason {
+		case constants.EndReasonKO:
+			r.KOWins++
+		case constants.EndReasonSurrender:
+			r.SurrenderWins++
+		}
+	case MatchLoss:
+		r.Losses++
+		switch match.EndReason {
+		case constants.EndReasonKO:
+			r.KOLosses++
+		case constants.EndReasonSurrender:
+			r.SurrenderLosses++
+		}
+	case MatchDraw:
+		r.Draws++
+	}
+	r.Rating = match.RatingAfter
+}
+
+// GetBattleRecord - Lấy bản sao thành tích đấu của player
+func (p *Player) GetBattleRecord() BattleRecord {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	record := p.data.BattleRecord
+	record.Matches = make([]MatchRecord, len(p.data.BattleRecord.Matches))
+	copy(record.Matches, p.data.BattleRecord.Matches)
+	return record
+}
+
+// GetRating - Lấy rating hiện tại của player
+func (p *Player) GetRating() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.data.BattleRecord.Rating
+}
+
+// RecordMatch - Ghi kết quả trận đấu vào hồ sơ, cập nhật bảng xếp hạng và lưu file
+func (p *Player) RecordMatch(match MatchRecord) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.data.BattleRecord.add(match)
+	if match.Rated {
+		leaderboard.update(p.data.ID, p.data.BattleRecord)
+	}
+	return p.saveToFile()
+}
+
+// LoadBattleRecord - Đọc thành tích đấu của player từ file (kể cả khi player offline)
+func LoadBattleRecord(id string) (BattleRecord, error) {
+	if err := ValidatePlayerID(id); err != nil {
+		return BattleRecord{}, err
+	}
+	data, err := readPlayerData(filepath.Join(constants.PlayerInventoryDir, fmt.Sprintf("%s.json", id)))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return newBattleRecord(), nil
+		}
+		return BattleRecord{}, err
+	}
+	return data.BattleRecord, nil
+}
+
+// leaderboardIndex - Thành tích xếp hạng của mọi player, đọc từ thư mục player một lần
+// rồi được RecordMatch cập nhật trong bộ nhớ
+type leaderboardIndex struct {
+	entries map[string]LeaderboardEntry
+	loaded  bool
+	mu      sync.Mutex
+}
+
+var leaderboard = &leaderboardIndex{entries: make(map[string]LeaderboardEntry)}
+
+// update - Ghi thành tích mới nhất của player vào index
+func (l *leaderboardIndex) update(id string, record BattleRecord) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries[id] = leaderboardEntry(id, record)
+}
+
+// load - Đọc thành tích từ file ở lần truy vấn đầu tiên (cần giữ l.mu).
+// Player đã được update trước đó giữ nguyên vì dữ liệu trong bộ nhớ mới hơn.
+func (l *leaderboardIndex) load() error {
+	if l.loaded {
+		return nil
+	}
+
+	files, err := os.ReadDir(constants.PlayerInventoryDir)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read player directory: %v", err)
+	}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		data, err := readPlayerData(filepath.Join(constants.PlayerInventoryDir, file.Name()))
+		if err != nil {
+			continue
+		}
+		if _, exists := l.entries[data.ID]; !exists {
+			l.entries[data.ID] = leaderboardEntry(data.ID, data.BattleRecord)
+		}
+	}
+	l.loaded = true
+	return nil
+}
+
+func leaderboardEntry(id string, record BattleRecord) LeaderboardEntry {
+	return LeaderboardEntry{
+		PlayerID: id,
+		Rating:   record.Rating,
+		Wins:     record.Wins,
+		Losses:   record.Losses,
+		Draws:    record.Draws,
+	}
+}
+
+// Leaderboard - Bảng xếp hạng theo rating của các player đã đấu ít nhất một trận xếp hạng
+func Leaderboard(limit int) ([]LeaderboardEntry, error) {
+	leaderboard.mu.Lock()
+	if err := leaderboard.load(); err != nil {
+		leaderboard.mu.Unlock()
+		return nil, err
+	}
+	entries := make([]LeaderboardEntry, 0, len(leaderboard.entries))
+	for _, entry := range leaderboard.entries {
+		if entry.Wins+entry.Losses+entry.Draws > 0 {
+			entries = append(entries, entry)
+		}
+	}
+	leaderboard.mu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Rating != entries[j].Rating {
+			return entries[i].Rating > entries[j].Rating
+		}
+		if entries[i].Wins != entries[j].Wins {
+			return entries[i].Wins > entries[j].Wins
+		}
+		return entries[i].PlayerID < entries[j].PlayerID
+	})
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	return entries, nil
+}
+
+// readPlayerData - Đọc dữ liệu player từ file mà không khởi tạo Player
+func readPlayerData(filename string) (PlayerData, error) {
+	var playerData PlayerData
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return playerData, fmt.Errorf("failed to read player data: %w", err)
+	}
+	if err := json.Unmarshal(data, &playerData); err != nil {
+		return playerData, fmt.Errorf("failed to parse player data: %v", err)
+	}
+	if playerData.BattleRecord.Games() == 0 && playerData.BattleRecord.Rating == 0 {
+		// File cũ chưa có thành tích đấu
+		playerData.BattleRecord = newBattleRecord()
+	}
+	for _, pokemon := range playerData.PokemonList {
+		if pokemon.Moves == nil {
+			// File cũ chưa có bộ đòn - học theo level hiện tại
+			pokemon.LearnMoves()
+		}
+	}
+	return playerData, nil
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+)
+
+func TestBattleRecordUnratedMatchesCountedSeparately(t *testing.T) {
+	record := newBattleRecord()
+	record.add(MatchRecord{Outcome: MatchWin, EndReason: constants.EndReasonKO, Rated: true,
+		RatingBefore: constants.InitialRating, RatingAfter: constants.InitialRating + 16})
+	record.add(MatchRecord{Outcome: MatchWin, EndReason: constants.EndReasonKO,
+		RatingBefore: constants.InitialRating + 16, RatingAfter: constants.InitialRating + 16})
+	record.add(MatchRecord{Outcome: MatchLoss, EndReason: constants.EndReasonSurrender})
+	record.add(MatchRecord{Outcome: MatchDraw, EndReason: constants.EndReasonExpired})
+
+	if record.Wins != 1 || record.Losses != 0 || record.Draws != 0 || record.KOWins != 1 || record.SurrenderLosses != 0 {
+		t.Errorf("rated stats = %+v, want only the rated KO win", record)
+	}
+	if record.UnratedWins != 1 || record.UnratedLosses != 1 || record.UnratedDraws != 1 {
+		t.Errorf("unrated stats = %d/%d/%d, want 1/1/1", record.UnratedWins, record.UnratedLosses, record.UnratedDraws)
+	}
+	if record.Rating != constants.InitialRating+16 {
+		t.Errorf("rating = %d, want %d", record.Rating, constants.InitialRating+16)
+	}
+	if len(record.Matches) != 4 {
+		t.Errorf("history has %d matches, want 4", len(record.Matches))
+	}
+}
+
+func TestLeaderboardFollowsRecordMatch(t *testing.T) {
+	rated, _ := newTestPlayer(t, "leaderboard-rated")
+	unrated, _ := newTestPlayer(t, "leaderboard-unrated")
+
+	if err := unrated.RecordMatch(MatchRecord{Outcome: MatchWin, OpponentID: "npc"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rated.RecordMatch(MatchRecord{Outcome: MatchWin, Rated: true, RatingAfter: 5000}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Leaderboard(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 || entries[0].PlayerID != "leaderboard-rated" || entries[0].Rating != 5000 || entries[0].Wins != 1 {
+		t.Fatalf("leaderboard = %+v, want leaderboard-rated first with rating 5000", entries)
+	}
+	for _, entry := range entries {
+		if entry.PlayerID == "leaderboard-unrated" {
+			t.Errorf("player with only unrated matches is on the leaderboard")
+		}
+	}
+
+	// Trận sau được phản ánh ngay, không cần đọc lại file
+	if err := rated.RecordMatch(MatchRecord{Outcome: MatchLoss, Rated: true, RatingAfter: 4990}); err != nil {
+		t.Fatal(err)
+	}
+	entries, err = Leaderboard(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Rating != 4990 || entries[0].Losses != 1 || entries[0].Rank != 1 {
+		t.Errorf("leaderboard = %+v, want rating 4990 with one loss", entries)
+	}
+}
+
+// TestLoadBattleRecordRejectsPathTraversal - ID không hợp lệ không được đọc file ngoài PlayerInventoryDir
+func TestLoadBattleRecordRejectsPathTraversal(t *testing.T) {
+	outside := filepath.Join(constants.PlayerInventoryDir, "..", "outside.json")
+	if err := os.WriteFile(outside, []byte(`{"id":"outside","battle_record":{"wins":7}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Remove(outside) })
+
+	if record, err := LoadBattleRecord("../outside"); err == nil {
+		t.Errorf("LoadBattleRecord read %+v from outside the player directory", record)
+	}
+}
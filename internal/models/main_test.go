@@ -0,0 +1,26 @@
+package models
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/clock"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/config"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/random"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/testutil"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.RunInTempDir(m))
+}
+
+// newTestPlayer - Player mới (xóa file của lần chạy trước) chạy theo đồng hồ giả, dừng auto-save khi test kết thúc
+func newTestPlayer(t *testing.T, id string) (*Player, *clock.Fake) {
+	t.Helper()
+	os.Remove(playerFile(id))
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	player := NewPlayerWithSources(id, config.Default(), clk, random.New(1))
+	t.Cleanup(func() { player.Cleanup() })
+	return player, clk
+}
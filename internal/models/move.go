@@ -0,0 +1,94 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/database"
+)
+
+// Move - Một đòn Pokemon đã học, kèm PP còn lại
+type Move struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Category string `json:"category"`
+	Power    int    `json:"power"`
+	Accuracy int    `json:"accuracy"`
+	PP       int    `json:"pp"`
+	MaxPP    int    `json:"max_pp"`
+}
+
+func newMove(entry database.MoveEntry) Move {
+	return Move{
+		Name:     entry.Name,
+		Type:     entry.Type,
+		Category: entry.Category,
+		Power:    entry.Power,
+		Accuracy: entry.Accuracy,
+		PP:       entry.PP,
+		MaxPP:    entry.PP,
+	}
+}
+
+// LearnMoves - Học các đòn theo learnset của loài ở level hiện tại, thay thế bộ đòn cũ
+func (p *Pokemon) LearnMoves() error {
+	moveDex, err := database.GetMoveDex()
+	if err != nil {
+		return err
+	}
+
+	p.Moves = nil
+	for _, entry := range moveDex.KnownMoves(p.Name, p.Level) {
+		p.Moves = append(p.Moves, newMove(entry))
+	}
+	return nil
+}
+
+// learnMovesAt - Học các đòn mới ở level vừa đạt, quên đòn học lâu nhất khi đã nhớ đủ MaxMoves đòn
+func (p *Pokemon) learnMovesAt(level int) {
+	moveDex, err := database.GetMoveDex()
+	if err != nil {
+		return
+	}
+
+	for _, entry := range moveDex.MovesLearnedAt(p.Name, level) {
+		if p.FindMove(entry.Name) != nil {
+			continue
+		}
+		moves := append([]Move(nil), p.Moves...)
+		if len(moves) >= constants.MaxMoves {
+			moves = moves[1:]
+		}
+		p.Moves = append(moves, newMove(entry))
+	}
+}
+
+// FindMove - Tìm đòn Pokemon đã học theo tên (không phân biệt hoa thường)
+func (p *Pokemon) FindMove(name string) *Move {
+	for i := range p.Moves {
+		if strings.EqualFold(p.Moves[i].Name, name) {
+			return &p.Moves[i]
+		}
+	}
+	return nil
+}
+
+// HasUsableMoves - Pokemon còn đòn đã học với PP > 0; nếu không chỉ dùng được đòn generic
+func (p *Pokemon) HasUsableMoves() bool {
+	for _, move := range p.Moves {
+		if move.PP > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Clone - Bản sao độc lập (kể cả types và bộ đòn) để battle sửa HP, PP mà không ảnh hưởng bản gốc
+func (p *Pokemon) Clone() *Pokemon {
+	clone := *p
+	clone.Types = append([]string(nil), p.Types...)
+	if p.Moves != nil {
+		clone.Moves = append([]Move(nil), p.Moves...)
+	}
+	return &clone
+}
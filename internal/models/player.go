@@ -2,15 +2,19 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"math/rand"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sync"
 	"time"
 
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/clock"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/config"
 	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/random"
 )
 
 // PlayerData - Cấu trúc dữ liệu để lưu vào file JSON
@@ -22,6 +26,9 @@ type PlayerData struct {
 	BattleTeam      []string            `json:"battle_team,omitempty"`
 	LastMoveTime    time.Time           `json:"last_move_time"`
 	AutoModeEndTime time.Time           `json:"auto_mode_end_time,omitempty"`
+	AutoStrategy    string              `json:"auto_strategy,omitempty"`
+	AutoSteps       int                 `json:"auto_steps,omitempty"` // Số bước tự động đã thực hiện, dùng cho chiến lược quét
+	BattleRecord    BattleRecord        `json:"battle_record"`
 	LastSaveTime    time.Time           `json:"last_save_time"`
 }
 
@@ -37,26 +44,38 @@ type Player struct {
 	mu           sync.RWMutex
 	stopAutoSave chan struct{}
 	isOnline     bool
+	maxInventory int // Giới hạn inventory theo config
+	clock        clock.Clock
 }
 
-// NewPlayer - Tạo player mới
-func NewPlayer(id string) *Player {
+// NewPlayer - Tạo player mới ở vị trí ngẫu nhiên trong world theo cfg
+func NewPlayer(id string, cfg config.Config) *Player {
+	return NewPlayerWithSources(id, cfg, clock.Real(), random.Global())
+}
+
+// NewPlayerWithSources - Tạo player mới, vị trí ban đầu chọn bằng rng;
+// cooldown di chuyển, auto mode và auto-save chạy theo clk
+func NewPlayerWithSources(id string, cfg config.Config, clk clock.Clock, rng random.Source) *Player {
+	now := clk.Now()
 	player := &Player{
 		data: PlayerData{
 			ID:          id,
 			PokemonList: make(map[string]*Pokemon),
 			Position: Position{
-				X: rand.Intn(constants.WorldWidth),
-				Y: rand.Intn(constants.WorldHeight),
+				X: rng.Intn(cfg.World.Width),
+				Y: rng.Intn(cfg.World.Height),
 			},
-			LastMoveTime: time.Now(),
-			LastSaveTime: time.Now(),
+			LastMoveTime: now,
+			LastSaveTime: now,
+			BattleRecord: newBattleRecord(),
 		},
 		stopAutoSave: make(chan struct{}),
 		isOnline:     true,
+		maxInventory: cfg.Player.MaxInventory,
+		clock:        clk,
 	}
 
-	go player.startAutoSave()
+	player.startAutoSave()
 	return player
 }
 
@@ -67,19 +86,40 @@ func (p *Player) GetID() string {
 	return p.data.ID
 }
 
-// AddPokemon - Thêm pokemon vào inventory
+// AddPokemon - Thêm pokemon vào inventory và lưu file
 func (p *Player) AddPokemon(pokemon *Pokemon) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	if len(p.data.PokemonList) >= constants.MaxPokemonInventory {
+	if err := p.addPokemon(pokemon); err != nil {
+		return err
+	}
+	return p.saveToFile()
+}
+
+// CollectPokemon - Thêm pokemon vào inventory trong bộ nhớ, không ghi file.
+// Dùng khi đang giữ lock của world; người gọi lưu lại bằng Save sau khi thả lock.
+func (p *Player) CollectPokemon(pokemon *Pokemon) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.addPokemon(pokemon)
+}
+
+// addPokemon - Thêm bản sao pokemon vào inventory nếu còn chỗ (cần giữ p.mu)
+func (p *Player) addPokemon(pokemon *Pokemon) error {
+	if len(p.data.PokemonList) >= p.maxInventory {
 		return fmt.Errorf(constants.ErrInventoryFull)
 	}
 
 	// Deep copy của pokemon để tránh reference issues
-	pokemonCopy := *pokemon
-	p.data.PokemonList[pokemon.Number] = &pokemonCopy
+	p.data.PokemonList[pokemon.Number] = pokemon.Clone()
+	return nil
+}
 
+// Save - Lưu player data vào file JSON
+func (p *Player) Save() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	return p.saveToFile()
 }
 
@@ -94,8 +134,7 @@ func (p *Player) GetPokemon(number string) (*Pokemon, error) {
 	}
 
 	// Return deep copy để tránh race conditions
-	pokemonCopy := *pokemon
-	return &pokemonCopy, nil
+	return pokemon.Clone(), nil
 }
 
 // SelectBattleTeam - Chọn team cho battle
@@ -103,13 +142,20 @@ func (p *Player) SelectBattleTeam(pokemonNumbers []string) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	if len(pokemonNumbers) != constants.MaxBattlePokemon {
-		return fmt.Errorf("invalid team size: expected %d, got %d",
-			constants.MaxBattlePokemon, len(pokemonNumbers))
+	// Số lượng cụ thể được kiểm tra theo thể thức battle khi thách đấu
+	if len(pokemonNumbers) < 1 || len(pokemonNumbers) > constants.MaxBattleTeamSize {
+		return fmt.Errorf("invalid team size: expected 1 to %d, got %d",
+			constants.MaxBattleTeamSize, len(pokemonNumbers))
 	}
 
 	// Validate từng Pokemon
+	selected := make(map[string]bool)
 	for _, num := range pokemonNumbers {
+		if selected[num] {
+			return fmt.Errorf("pokemon %s selected more than once", num)
+		}
+		selected[num] = true
+
 		pokemon, exists := p.data.PokemonList[num]
 		if !exists {
 			return fmt.Errorf("pokemon %s not found in inventory", num)
@@ -127,36 +173,29 @@ func (p *Player) SelectBattleTeam(pokemonNumbers []string) error {
 	return p.saveToFile()
 }
 
-// Move - Di chuyển player
-func (p *Player) Move(direction constants.Direction) error {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+// CanMove - Kiểm tra player được phép di chuyển: đang online và không quá MovementSpeed ô mỗi giây.
+// Việc di chuyển do world thực hiện (pokecat.Grid.Move) để vị trí và ô luôn khớp nhau.
+func (p *Player) CanMove() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
 
 	if !p.isOnline {
 		return fmt.Errorf("player is offline")
 	}
-
-	if time.Since(p.data.LastMoveTime).Seconds() < float64(1/constants.MovementSpeed) {
+	if p.clock.Now().Sub(p.data.LastMoveTime) < time.Second/constants.MovementSpeed {
 		return fmt.Errorf("movement too frequent")
 	}
+	return nil
+}
 
-	newPos := p.data.Position
-	switch direction {
-	case constants.DirectionUp:
-		newPos.Y = (newPos.Y - 1 + constants.WorldHeight) % constants.WorldHeight
-	case constants.DirectionDown:
-		newPos.Y = (newPos.Y + 1) % constants.WorldHeight
-	case constants.DirectionLeft:
-		newPos.X = (newPos.X - 1 + constants.WorldWidth) % constants.WorldWidth
-	case constants.DirectionRight:
-		newPos.X = (newPos.X + 1) % constants.WorldWidth
-	default:
-		return fmt.Errorf("invalid direction")
-	}
+// SetPosition - Ghi nhận vị trí mới sau khi world đã áp dụng nước đi.
+// Chỉ cập nhật trong bộ nhớ vì được gọi khi world giữ lock của ô; auto-save và Cleanup sẽ lưu file.
+func (p *Player) SetPosition(pos Position) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	p.data.Position = newPos
-	p.data.LastMoveTime = time.Now()
-	return p.saveToFile()
+	p.data.Position = pos
+	p.data.LastMoveTime = p.clock.Now()
 }
 
 // SaveToFile - Lưu player data vào file JSON
@@ -168,7 +207,7 @@ func (p *Player) saveToFile() error {
 	filename := filepath.Join(constants.PlayerInventoryDir, fmt.Sprintf("%s.json", p.data.ID))
 
 	// Cập nhật thời gian save
-	p.data.LastSaveTime = time.Now()
+	p.data.LastSaveTime = p.clock.Now()
 
 	data, err := json.MarshalIndent(p.data, "", "    ")
 	if err != nil {
@@ -189,52 +228,72 @@ func (p *Player) saveToFile() error {
 	return nil
 }
 
-// LoadFromFile - Load player data từ file JSON
-func LoadPlayer(id string) (*Player, error) {
+// playerIDPattern - ID player được dùng làm tên file trong PlayerInventoryDir nên chỉ cho phép chữ, số, '_' và '-'
+var playerIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// ValidatePlayerID - Kiểm tra ID player trước khi đọc hoặc ghi file của player
+func ValidatePlayerID(id string) error {
+	if !playerIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid player id %q: expected 1-32 letters, digits, '_' or '-'", id)
+	}
+	return nil
+}
+
+// LoadFromFile - Load player data từ file JSON, player chưa có file thì tạo mới theo cfg
+func LoadPlayer(id string, cfg config.Config) (*Player, error) {
+	return LoadPlayerWithSources(id, cfg, clock.Real(), random.Global())
+}
+
+// LoadPlayerWithSources - Như LoadPlayer, player chạy theo clk và rng
+func LoadPlayerWithSources(id string, cfg config.Config, clk clock.Clock, rng random.Source) (*Player, error) {
+	if err := ValidatePlayerID(id); err != nil {
+		return nil, err
+	}
 	filename := filepath.Join(constants.PlayerInventoryDir, fmt.Sprintf("%s.json", id))
 
-	data, err := os.ReadFile(filename)
+	playerData, err := readPlayerData(filename)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return NewPlayer(id), nil
+		if errors.Is(err, os.ErrNotExist) {
+			return NewPlayerWithSources(id, cfg, clk, rng), nil
 		}
-		return nil, fmt.Errorf("failed to read player data: %v", err)
-	}
-
-	var playerData PlayerData
-	if err := json.Unmarshal(data, &playerData); err != nil {
-		return nil, fmt.Errorf("failed to parse player data: %v", err)
+		return nil, err
 	}
+	// Battle không tồn tại qua lần đăng nhập trước (server dừng hoặc player bị xử thua khi thoát)
+	playerData.CurrentBattle = ""
 
 	player := &Player{
 		data:         playerData,
 		stopAutoSave: make(chan struct{}),
 		isOnline:     true,
+		maxInventory: cfg.Player.MaxInventory,
+		clock:        clk,
 	}
 
-	go player.startAutoSave()
+	player.startAutoSave()
 	return player, nil
 }
 
 // startAutoSave - Auto-save routine
 func (p *Player) startAutoSave() {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			p.mu.Lock()
-			if p.isOnline {
-				if err := p.saveToFile(); err != nil {
-					log.Printf("Auto-save failed for player %s: %v", p.data.ID, err)
+	ticker := p.clock.NewTicker(30 * time.Second)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				p.mu.Lock()
+				if p.isOnline {
+					if err := p.saveToFile(); err != nil {
+						log.Printf("Auto-save failed for player %s: %v", p.data.ID, err)
+					}
 				}
+				p.mu.Unlock()
+			case <-p.stopAutoSave:
+				return
 			}
-			p.mu.Unlock()
-		case <-p.stopAutoSave:
-			return
 		}
-	}
+	}()
 }
 
 // Cleanup - Dọn dẹp resources
@@ -269,3 +328,19 @@ func (p *Player) IsInBattle() bool {
 	defer p.mu.RUnlock()
 	return p.data.CurrentBattle != ""
 }
+
+// GetCurrentBattle - Lấy ID battle hiện tại của player
+func (p *Player) GetCurrentBattle() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.data.CurrentBattle
+}
+
+// SetCurrentBattle - Gán battle hiện tại (chuỗi rỗng khi rời battle)
+func (p *Player) SetCurrentBattle(battleID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.data.CurrentBattle = battleID
+	return p.saveToFile()
+}
@@ -0,0 +1,173 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/clock"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/config"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/database"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/random"
+)
+
+func newTestPokemon(t *testing.T, name string) *Pokemon {
+	t.Helper()
+	pokedex, err := database.GetPokedex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := pokedex.Find(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pokemon, err := NewPokemon(entry.ToMap(), 5, constants.DefaultEV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pokemon
+}
+
+func playerFile(id string) string {
+	return filepath.Join(constants.PlayerInventoryDir, id+".json")
+}
+
+func TestCollectPokemonDefersSave(t *testing.T) {
+	player, _ := newTestPlayer(t, "collect-defers-save")
+	pikachu := newTestPokemon(t, "Pikachu")
+
+	if err := player.CollectPokemon(pikachu); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := player.GetPokemon(pikachu.Number); err != nil {
+		t.Fatalf("collected pokemon missing from inventory: %v", err)
+	}
+	if _, err := os.Stat(playerFile("collect-defers-save")); !os.IsNotExist(err) {
+		t.Fatalf("player file written before Save (stat err = %v)", err)
+	}
+
+	if err := player.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := readPlayerData(playerFile("collect-defers-save"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := data.PokemonList[pikachu.Number]; !exists {
+		t.Errorf("saved inventory does not contain %s", pikachu.Name)
+	}
+}
+
+func TestCollectPokemonRespectsInventoryLimit(t *testing.T) {
+	player, _ := newTestPlayer(t, "collect-inventory-full")
+	player.maxInventory = 1
+
+	if err := player.CollectPokemon(newTestPokemon(t, "Pikachu")); err != nil {
+		t.Fatal(err)
+	}
+	err := player.CollectPokemon(newTestPokemon(t, "Bulbasaur"))
+	if err == nil || err.Error() != constants.ErrInventoryFull {
+		t.Fatalf("second collect error = %v, want %q", err, constants.ErrInventoryFull)
+	}
+}
+
+func TestMoveCooldown(t *testing.T) {
+	player, clk := newTestPlayer(t, "move-cooldown")
+	cooldown := time.Second / constants.MovementSpeed
+
+	// Player mới tạo vừa được ghi nhận di chuyển
+	if err := player.CanMove(); err == nil {
+		t.Fatalf("player can move immediately after creation")
+	}
+	clk.Advance(cooldown)
+	if err := player.CanMove(); err != nil {
+		t.Fatalf("player cannot move after the cooldown: %v", err)
+	}
+
+	player.SetPosition(Position{X: 1, Y: 1})
+	clk.Advance(cooldown - time.Nanosecond)
+	if err := player.CanMove(); err == nil {
+		t.Errorf("player can move before the cooldown ends")
+	}
+	clk.Advance(time.Nanosecond)
+	if err := player.CanMove(); err != nil {
+		t.Errorf("player cannot move exactly at the end of the cooldown: %v", err)
+	}
+}
+
+// waitFor - Chờ routine chạy nền đạt điều kiện sau khi đồng hồ giả được tiến
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAutoSaveStopsOnCleanup(t *testing.T) {
+	filename := playerFile("auto-save-stop")
+	os.Remove(filename)
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	player := NewPlayerWithSources("auto-save-stop", config.Default(), clk, random.New(1))
+
+	if clk.Waiters() != 1 {
+		t.Fatalf("%d clock waiters, want the auto-save ticker", clk.Waiters())
+	}
+	clk.Advance(30 * time.Second)
+	waitFor(t, "auto-save", func() bool {
+		_, err := os.Stat(filename)
+		return err == nil
+	})
+
+	if err := player.Cleanup(); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "auto-save ticker to stop", func() bool { return clk.Waiters() == 0 })
+
+	if err := os.Remove(filename); err != nil {
+		t.Fatal(err)
+	}
+	clk.Advance(time.Minute)
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Errorf("player saved after Cleanup (stat err = %v)", err)
+	}
+}
+
+func TestValidatePlayerID(t *testing.T) {
+	tests := []struct {
+		id    string
+		valid bool
+	}{
+		{"ash", true},
+		{"Trainer_01-b", true},
+		{"abcdefghijklmnopqrstuvwxyz012345", true},
+		{"", false},
+		{"abcdefghijklmnopqrstuvwxyz0123456", false},
+		{"../../etc/x", false},
+		{"a/b", false},
+		{`a\b`, false},
+		{"a.json", false},
+		{"ash ketchum", false},
+	}
+	for _, test := range tests {
+		if err := ValidatePlayerID(test.id); (err == nil) != test.valid {
+			t.Errorf("ValidatePlayerID(%q) = %v, want valid %v", test.id, err, test.valid)
+		}
+	}
+}
+
+// TestLoadPlayerRejectsPathTraversal - ID không hợp lệ bị từ chối trước khi đọc hoặc tạo file
+func TestLoadPlayerRejectsPathTraversal(t *testing.T) {
+	id := "../escaped"
+	if _, err := LoadPlayer(id, config.Default()); err == nil {
+		t.Fatalf("LoadPlayer(%q) succeeded", id)
+	}
+	if _, err := os.Stat(playerFile(id)); !os.IsNotExist(err) {
+		t.Errorf("file %s was touched: %v", playerFile(id), err)
+	}
+}
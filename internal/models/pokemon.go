@@ -1,14 +1,13 @@
 package models
 
 import (
-	"encoding/json"
 	"fmt"
 	"math"
-	"math/rand"
-	"os"
 	"strings"
 
 	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/database"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/random"
 )
 
 // Stats - Thông số cơ bản của Pokemon
@@ -35,6 +34,7 @@ type Pokemon struct {
 	BaseExp        int      `json:"base_exp"`
 	EV             float64  `json:"ev"`
 	IsDestroyed    bool     `json:"is_destroyed"`
+	Moves          []Move   `json:"moves,omitempty"` // Tối đa MaxMoves đòn, trống thì dùng đòn generic
 }
 
 // NewPokemon - Tạo Pokemon mới từ dữ liệu Pokedex
@@ -82,6 +82,17 @@ func NewPokemon(pokedexData map[string]interface{}, level int, ev float64) (*Pok
 
 // NewRandomPokemon - Tạo Pokemon ngẫu nhiên với level và EV cho trước
 func NewRandomPokemon(level int, ev float64) (*Pokemon, error) {
+	return NewRandomPokemonFrom(random.Global(), level, ev)
+}
+
+// NewRandomPokemonFrom - Tạo Pokemon ngẫu nhiên, loài được chọn bằng rng
+func NewRandomPokemonFrom(rng random.Source, level int, ev float64) (*Pokemon, error) {
+	return NewRandomPokemonWeighted(rng, level, ev, nil)
+}
+
+// NewRandomPokemonWeighted - Như NewRandomPokemonFrom nhưng chọn loài theo trọng số weight
+// (ví dụ bảng spawn của biome); weight nil thì chọn đều
+func NewRandomPokemonWeighted(rng random.Source, level int, ev float64, weight func(*database.PokedexEntry) float64) (*Pokemon, error) {
 	// Validate input
 	if level < 1 || level > constants.MaxLevel {
 		return nil, fmt.Errorf("invalid level: %d", level)
@@ -91,40 +102,21 @@ func NewRandomPokemon(level int, ev float64) (*Pokemon, error) {
 	}
 
 	// Load pokedex data
-	pokedexData, err := loadPokedexData()
+	pokedex, err := database.GetPokedex()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load pokedex: %v", err)
 	}
 
 	// Random select một Pokemon từ Pokedex
-	pokemons := make([]map[string]interface{}, 0)
-	for _, data := range pokedexData {
-		if pokemon, ok := data.(map[string]interface{}); ok {
-			pokemons = append(pokemons, pokemon)
-		}
-	}
-
-	if len(pokemons) == 0 {
-		return nil, fmt.Errorf("no pokemon data available")
-	}
-
-	randomPokemon := pokemons[rand.Intn(len(pokemons))]
-	return NewPokemon(randomPokemon, level, ev)
-}
-
-// loadPokedexData - Load dữ liệu từ pokedex.json
-func loadPokedexData() (map[string]interface{}, error) {
-	data, err := os.ReadFile("data/pokedex.json")
+	randomPokemon := pokedex.RandomWeighted(rng, weight)
+	pokemon, err := NewPokemon(randomPokemon.ToMap(), level, ev)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read pokedex.json: %v", err)
+		return nil, err
 	}
 
-	var pokedex map[string]interface{}
-	if err := json.Unmarshal(data, &pokedex); err != nil {
-		return nil, fmt.Errorf("failed to parse pokedex data: %v", err)
-	}
-
-	return pokedex, nil
+	// Không có dữ liệu đòn thì Pokemon dùng đòn generic
+	pokemon.LearnMoves()
+	return pokemon, nil
 }
 
 // AddExperience - Thêm exp và level up nếu đủ điều kiện
@@ -145,6 +137,10 @@ func (p *Pokemon) AddExperience(exp int) (bool, error) {
 	if p.AccumulatedExp >= requiredExp {
 		p.Level++
 		p.recalculateStats()
+		if p.Moves != nil {
+			// Pokemon chỉ dùng đòn generic không học lẻ tẻ từng đòn
+			p.learnMovesAt(p.Level)
+		}
 		return true, nil
 	}
 	return false, nil
@@ -242,6 +238,17 @@ func (p *Pokemon) IsAlive() bool {
 	return !p.IsDestroyed && p.CurrentStats.HP > 0
 }
 
+// SetLevel - Đặt level (dùng khi thể thức battle chuẩn hóa level), exp tích lũy được reset
+func (p *Pokemon) SetLevel(level int) error {
+	if level < 1 || level > constants.MaxLevel {
+		return fmt.Errorf(constants.ErrInvalidLevel)
+	}
+	p.Level = level
+	p.AccumulatedExp = 0
+	p.recalculateStats()
+	return nil
+}
+
 // GetLevel - Lấy level hiện tại
 func (p *Pokemon) GetLevel() int {
 	return p.Level
@@ -0,0 +1,50 @@
+package random
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Source - Nguồn số ngẫu nhiên có thể thay thế (RandomSource); *rand.Rand thỏa mãn interface này
+type Source interface {
+	Intn(n int) int
+	Float64() float64
+}
+
+// lockedSource - *rand.Rand có khóa để dùng chung giữa các goroutine
+type lockedSource struct {
+	rng *rand.Rand
+	mu  sync.Mutex
+}
+
+type globalSource struct{}
+
+// New - Nguồn ngẫu nhiên có seed, cùng seed cho cùng dãy số; an toàn khi dùng từ nhiều goroutine
+func New(seed int64) Source {
+	return &lockedSource{rng: rand.New(rand.NewSource(seed))}
+}
+
+// Global - Nguồn dùng math/rand toàn cục
+func Global() Source {
+	return globalSource{}
+}
+
+func (s *lockedSource) Intn(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Intn(n)
+}
+
+func (s *lockedSource) Float64() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Float64()
+}
+
+func (globalSource) Intn(n int) int {
+	return rand.Intn(n)
+}
+
+func (globalSource) Float64() float64 {
+	return rand.Float64()
+}
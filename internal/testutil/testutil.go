@@ -0,0 +1,63 @@
+// Package testutil - Tiện ích dùng chung cho các bài kiểm thử
+package testutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+)
+
+// RunInTempDir - Chạy các test của package trong một thư mục tạm có sẵn dữ liệu Pokedex và đòn đánh,
+// để file player, replay và giải đấu không ghi vào thư mục data của repo. Dùng trong TestMain.
+func RunInTempDir(m *testing.M) int {
+	root, err := moduleRoot()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	dir, err := os.MkdirTemp("", "pokecat-test-")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+
+	for _, path := range []string{constants.PokedexPath, constants.MovesPath, constants.NPCRosterPath} {
+		target := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if err := os.Symlink(filepath.Join(root, path), target); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	if err := os.Chdir(dir); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return m.Run()
+}
+
+// moduleRoot - Thư mục chứa go.mod, tìm ngược từ thư mục hiện tại
+func moduleRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("go.mod not found")
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,309 @@
+package network
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/game/pokebat"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/game/pokecat"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/game/tournament"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+// MessageType - Loại message trao đổi giữa client và server
+type MessageType string
+
+// Client -> Server
+const (
+	MsgLogin                MessageType = "login"
+	MsgSelectTeam           MessageType = "select_team"
+	MsgChallenge            MessageType = "challenge"
+	MsgAcceptChallenge      MessageType = "accept_challenge"
+	MsgDeclineChallenge     MessageType = "decline_challenge"
+	MsgJoinQueue            MessageType = "join_queue"
+	MsgLeaveQueue           MessageType = "leave_queue"
+	MsgReady                MessageType = "ready"
+	MsgBattleMove           MessageType = "battle_move"
+	MsgBattleSwitch         MessageType = "battle_switch"
+	MsgChallengeNPC         MessageType = "challenge_npc"
+	MsgSurrender            MessageType = "surrender"
+	MsgBattleCapture        MessageType = "battle_capture"
+	MsgSpectate             MessageType = "spectate"
+	MsgStopSpectating       MessageType = "stop_spectating"
+	MsgListBattles          MessageType = "list_battles"
+	MsgLeaderboard          MessageType = "leaderboard"
+	MsgPlayerRecord         MessageType = "player_record"
+	MsgTournamentCreate     MessageType = "tournament_create"
+	MsgTournamentRegister   MessageType = "tournament_register"
+	MsgTournamentUnregister MessageType = "tournament_unregister"
+	MsgTournamentStart      MessageType = "tournament_start"
+	MsgTournamentList       MessageType = "tournament_list"
+	MsgTournamentInfo       MessageType = "tournament_info"
+	MsgListFormats          MessageType = "list_formats"
+	MsgMove                 MessageType = "move"
+	MsgView                 MessageType = "view"
+	MsgStartAuto            MessageType = "start_auto"
+	MsgStopAuto             MessageType = "stop_auto"
+	MsgPing                 MessageType = "ping"
+)
+
+// Server -> Client
+const (
+	MsgOK                MessageType = "ok"
+	MsgError             MessageType = "error"
+	MsgPong              MessageType = "pong"
+	MsgChallengeReceived MessageType = "challenge_received"
+	MsgBattleCreated     MessageType = "battle_created"
+	MsgBattleEvent       MessageType = "battle_event"
+	MsgWorldEvent        MessageType = "world_event"  // Thay đổi trong vùng nhìn thấy của player
+	MsgWorldResync       MessageType = "world_resync" // Vùng nhìn thấy đầy đủ sau khi client bị lỡ event vì quá chậm
+)
+
+// Message - Phong bì chung cho mọi message, mỗi message là một dòng JSON
+type Message struct {
+	Type      MessageType     `json:"type"`
+	RequestID string          `json:"request_id,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+}
+
+// LoginRequest - Đăng nhập bằng player ID
+type LoginRequest struct {
+	PlayerID string `json:"player_id"`
+}
+
+// SelectTeamRequest - Chọn team cho battle
+type SelectTeamRequest struct {
+	Pokemon []string `json:"pokemon"`
+}
+
+// ChallengeRequest - Thách đấu một player đang online
+type ChallengeRequest struct {
+	OpponentID string `json:"opponent_id"`
+	Format     string `json:"format,omitempty"` // Rỗng là thể thức chuẩn
+}
+
+// ChallengeReply - Chấp nhận hoặc từ chối một lời thách đấu
+type ChallengeReply struct {
+	ChallengeID string `json:"challenge_id"`
+}
+
+// ChallengeInfo - Thông tin lời thách đấu gửi cho đối thủ
+type ChallengeInfo struct {
+	ChallengeID  string `json:"challenge_id"`
+	ChallengerID string `json:"challenger_id"`
+	Format       string `json:"format"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// QueueRequest - Vào hàng chờ ghép trận của một thể thức
+type QueueRequest struct {
+	Format string `json:"format,omitempty"`
+}
+
+// FormatList - Danh sách thể thức battle
+type FormatList struct {
+	Formats []pokebat.BattleFormat `json:"formats"`
+}
+
+// BattleMoveRequest - Chọn đòn đánh trong lượt
+type BattleMoveRequest struct {
+	Move   string `json:"move"`             // Tên đòn đã học, hoặc normal/special khi Pokemon không còn đòn dùng được
+	Target int    `json:"target,omitempty"` // Vị trí đối thủ (1, 2) ở battle đôi
+}
+
+// CaptureResult - Kết quả dùng lượt để bắt Pokemon hoang dã
+type CaptureResult struct {
+	Captured bool `json:"captured"`
+}
+
+// BattleSwitchRequest - Đổi sang Pokemon ở vị trí chỉ định trong team
+type BattleSwitchRequest struct {
+	Index int `json:"index"`
+}
+
+// ChallengeNPCRequest - Thách đấu một NPC trainer
+type ChallengeNPCRequest struct {
+	NPCID  string `json:"npc_id"`
+	Format string `json:"format,omitempty"`
+}
+
+// BattleInfo - Thông tin battle vừa được tạo
+type BattleInfo struct {
+	BattleID   string `json:"battle_id"`
+	OpponentID string `json:"opponent_id"`
+}
+
+// SpectateRequest - Xem một battle đang diễn ra
+type SpectateRequest struct {
+	BattleID string `json:"battle_id"`
+}
+
+// SpectateInfo - Trạng thái battle khi bắt đầu xem, gồm các event công khai đã xảy ra
+type SpectateInfo struct {
+	Battle  pokebat.BattleSummary `json:"battle"`
+	History []pokebat.Event       `json:"history"`
+}
+
+// BattleList - Danh sách battle đang diễn ra
+type BattleList struct {
+	Battles []pokebat.BattleSummary `json:"battles"`
+}
+
+// LeaderboardRequest - Lấy bảng xếp hạng, limit 0 dùng số dòng mặc định
+type LeaderboardRequest struct {
+	Limit int `json:"limit"`
+}
+
+// LeaderboardReply - Bảng xếp hạng theo rating
+type LeaderboardReply struct {
+	Entries []models.LeaderboardEntry `json:"entries"`
+}
+
+// PlayerRecordRequest - Xem thành tích đấu, player_id rỗng là của chính mình
+type PlayerRecordRequest struct {
+	PlayerID string `json:"player_id"`
+}
+
+// PlayerRecordReply - Rating, thống kê và lịch sử đấu của player
+type PlayerRecordReply struct {
+	PlayerID string              `json:"player_id"`
+	Record   models.BattleRecord `json:"record"`
+}
+
+// TournamentCreateRequest - Tạo giải, max_players 0 dùng mức tối đa, swiss_rounds 0 tự tính
+type TournamentCreateRequest struct {
+	Name         string            `json:"name"`
+	Format       tournament.Format `json:"format"`
+	BattleFormat string            `json:"battle_format,omitempty"`
+	MaxPlayers   int               `json:"max_players"`
+	SwissRounds  int               `json:"swiss_rounds"`
+}
+
+// TournamentRequest - Thao tác trên một giải theo ID
+type TournamentRequest struct {
+	TournamentID string `json:"tournament_id"`
+}
+
+// TournamentList - Danh sách giải
+type TournamentList struct {
+	Tournaments []tournament.Summary `json:"tournaments"`
+}
+
+// TournamentInfo - Toàn bộ trạng thái giải và bảng xếp hạng
+type TournamentInfo struct {
+	Tournament *tournament.Tournament `json:"tournament"`
+	Standings  []tournament.Standing  `json:"standings"`
+}
+
+// MoveRequest - Đi một ô theo hướng up, down, left hoặc right
+type MoveRequest struct {
+	Direction string `json:"direction"`
+}
+
+// MoveReply - Kết quả di chuyển: Pokemon tự động bắt được và vùng nhìn thấy ở vị trí mới
+type MoveReply struct {
+	Captured []string   `json:"captured,omitempty"` // Number của Pokemon bắt được
+	View     ViewWindow `json:"view"`
+}
+
+// ViewRequest - Xem vùng quanh player, radius 0 dùng bán kính mặc định
+type ViewRequest struct {
+	Radius int `json:"radius"`
+}
+
+// StartAutoRequest - Bật tự động di chuyển, strategy rỗng là random, duration 0 dùng thời gian mặc định
+type StartAutoRequest struct {
+	Strategy string `json:"strategy,omitempty"` // random, sweep hoặc nearest_spawn
+	Duration int    `json:"duration,omitempty"` // Giây
+}
+
+// AutoModeInfo - Chế độ tự động di chuyển vừa được bật
+type AutoModeInfo struct {
+	Strategy string    `json:"strategy"`
+	EndTime  time.Time `json:"end_time"`
+}
+
+// ViewPokemon - Pokemon hoang dã trong vùng nhìn thấy
+type ViewPokemon struct {
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Number string `json:"number"`
+	Name   string `json:"name"`
+	Level  int    `json:"level"`
+}
+
+// ViewPlayer - Player khác trong vùng nhìn thấy
+type ViewPlayer struct {
+	X        int    `json:"x"`
+	Y        int    `json:"y"`
+	PlayerID string `json:"player_id"`
+}
+
+// ViewWindow - Vùng nhìn thấy quanh player (có tính wrap-around của world)
+type ViewWindow struct {
+	Position models.Position `json:"position"`
+	Area     pokecat.Rect    `json:"area"`
+	Terrain  []string        `json:"terrain"` // Mỗi hàng của vùng, mỗi ký tự là địa hình một ô ('.' cỏ, '~' nước, '^' núi, 'o' hang động, '#' thành phố)
+	Pokemon  []ViewPokemon   `json:"pokemon"`
+	Players  []ViewPlayer    `json:"players"`
+}
+
+// ErrorPayload - Nội dung message lỗi
+type ErrorPayload struct {
+	Error string `json:"error"`
+}
+
+// NewMessage - Tạo message với payload được encode sang JSON
+func NewMessage(msgType MessageType, payload interface{}) (Message, error) {
+	msg := Message{Type: msgType}
+	if payload == nil {
+		return msg, nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return msg, fmt.Errorf("failed to encode payload: %v", err)
+	}
+	msg.Payload = data
+	return msg, nil
+}
+
+// DecodePayload - Giải mã payload của message vào struct đích
+func (m Message) DecodePayload(target interface{}) error {
+	if len(m.Payload) == 0 {
+		return fmt.Errorf("missing payload for %s", m.Type)
+	}
+	if err := json.Unmarshal(m.Payload, target); err != nil {
+		return fmt.Errorf("invalid payload for %s: %v", m.Type, err)
+	}
+	return nil
+}
+
+// WriteMessage - Ghi message dạng một dòng JSON
+func WriteMessage(w io.Writer, msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %v", err)
+	}
+	data = append(data, '\n')
+	_, err = w.Write(data)
+	return err
+}
+
+// ReadMessage - Đọc một message từ scanner theo dòng
+func ReadMessage(scanner *bufio.Scanner) (Message, error) {
+	var msg Message
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return msg, err
+		}
+		return msg, io.EOF
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+		return msg, fmt.Errorf("invalid message: %v", err)
+	}
+	return msg, nil
+}
@@ -0,0 +1,801 @@
+package network
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/config"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/constants"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/game/pokebat"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/game/pokecat"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/game/tournament"
+	"github.com/TaViKhang/pokecat-n-pokebat/internal/models"
+)
+
+const sessionBufferSize = 256
+
+// Session - Một kết nối TCP của client
+type Session struct {
+	conn      net.Conn
+	player    *models.Player
+	out       chan Message
+	done      chan struct{}
+	closeOnce sync.Once
+
+	spectateMu     sync.Mutex
+	stopSpectating func() // Hủy xem battle hiện tại, nil nếu không xem
+
+	worldMu     sync.Mutex
+	worldEvents *pokecat.Subscription // Event world quanh player, nil nếu chưa đăng nhập
+}
+
+// Server - TCP server nhận lệnh từ client và đẩy event battle xuống
+type Server struct {
+	manager     *pokebat.Manager
+	tournaments *tournament.Manager
+	world       *pokecat.Grid
+	config      config.Config
+	listener    net.Listener
+	sessions    map[string]*Session // playerID -> session đã đăng nhập
+	conns       int
+	mu          sync.RWMutex
+	wg          sync.WaitGroup
+	done        chan struct{}
+}
+
+// NewServer - Tạo server với giới hạn kết nối, timeout và cấu hình player theo cfg
+func NewServer(manager *pokebat.Manager, cfg config.Config) *Server {
+	server := &Server{
+		manager:  manager,
+		config:   cfg,
+		sessions: make(map[string]*Session),
+		done:     make(chan struct{}),
+	}
+	manager.OnBattleCreated = server.handleBattleCreated
+	return server
+}
+
+// SetTournaments - Bật các lệnh giải đấu
+func (s *Server) SetTournaments(tournaments *tournament.Manager) {
+	s.tournaments = tournaments
+}
+
+// SetWorld - Bật world: player đăng nhập sẽ vào grid và dùng được lệnh di chuyển, xem vùng xung quanh
+func (s *Server) SetWorld(world *pokecat.Grid) {
+	s.world = world
+}
+
+// OnlinePlayer - Tìm player đang đăng nhập (dùng cho tournament.PlayerLookup)
+func (s *Server) OnlinePlayer(playerID string) (*models.Player, bool) {
+	session, err := s.getSession(playerID)
+	if err != nil {
+		return nil, false
+	}
+	return session.player, true
+}
+
+// ListenAndServe - Lắng nghe và phục vụ kết nối cho tới khi Shutdown
+func (s *Server) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", addr, err)
+	}
+	s.listener = listener
+	log.Printf("Server listening on %s", addr)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-s.done:
+				return nil
+			default:
+				log.Printf("Accept failed: %v", err)
+				continue
+			}
+		}
+
+		s.mu.Lock()
+		if s.conns >= s.config.Network.MaxConnections {
+			s.mu.Unlock()
+			conn.Close()
+			continue
+		}
+		s.conns++
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.handleConn(conn)
+	}
+}
+
+// Shutdown - Đóng listener và mọi session
+func (s *Server) Shutdown() {
+	close(s.done)
+	if s.listener != nil {
+		s.listener.Close()
+	}
+
+	s.mu.RLock()
+	for _, session := range s.sessions {
+		session.close()
+	}
+	s.mu.RUnlock()
+	s.wg.Wait()
+}
+
+func (s *Server) handleConn(conn net.Conn) {
+	defer s.wg.Done()
+
+	session := &Session{
+		conn: conn,
+		out:  make(chan Message, sessionBufferSize),
+		done: make(chan struct{}),
+	}
+	go session.writeLoop(time.Duration(s.config.Network.WriteTimeout) * time.Second)
+
+	defer func() {
+		s.logout(session)
+		session.close()
+		s.mu.Lock()
+		s.conns--
+		s.mu.Unlock()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	for {
+		conn.SetReadDeadline(time.Now().Add(time.Duration(s.config.Network.ReadTimeout) * time.Second))
+		msg, err := ReadMessage(scanner)
+		if err != nil {
+			if err != io.EOF {
+				log.Printf("Connection %s closed: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+
+		reply, err := s.dispatch(session, msg)
+		if err != nil {
+			session.sendReply(msg.RequestID, MsgError, ErrorPayload{Error: err.Error()})
+			continue
+		}
+		session.sendReply(msg.RequestID, reply.Type, reply.payload)
+	}
+}
+
+// reply - Kết quả xử lý một request
+type reply struct {
+	Type    MessageType
+	payload interface{}
+}
+
+func ok(payload interface{}) reply {
+	return reply{Type: MsgOK, payload: payload}
+}
+
+// dispatch - Xử lý một message của client
+func (s *Server) dispatch(session *Session, msg Message) (reply, error) {
+	if msg.Type == MsgPing {
+		return reply{Type: MsgPong}, nil
+	}
+	if msg.Type == MsgLogin {
+		return s.handleLogin(session, msg)
+	}
+	if session.player == nil {
+		return reply{}, fmt.Errorf("not logged in")
+	}
+
+	playerID := session.player.GetID()
+	switch msg.Type {
+	case MsgSelectTeam:
+		var req SelectTeamRequest
+		if err := msg.DecodePayload(&req); err != nil {
+			return reply{}, err
+		}
+		return ok(nil), session.player.SelectBattleTeam(req.Pokemon)
+
+	case MsgChallenge:
+		var req ChallengeRequest
+		if err := msg.DecodePayload(&req); err != nil {
+			return reply{}, err
+		}
+		opponent, err := s.getSession(req.OpponentID)
+		if err != nil {
+			return reply{}, err
+		}
+		format, err := pokebat.GetFormat(req.Format)
+		if err != nil {
+			return reply{}, err
+		}
+		challenge, err := s.manager.Challenge(session.player, opponent.player, format)
+		if err != nil {
+			return reply{}, err
+		}
+		opponent.send(MsgChallengeReceived, ChallengeInfo{
+			ChallengeID:  challenge.ID,
+			ChallengerID: playerID,
+			Format:       challenge.Format,
+			ExpiresIn:    int(challenge.ExpiresAt.Sub(s.manager.Now()).Seconds()),
+		})
+		return ok(ChallengeReply{ChallengeID: challenge.ID}), nil
+
+	case MsgAcceptChallenge:
+		var req ChallengeReply
+		if err := msg.DecodePayload(&req); err != nil {
+			return reply{}, err
+		}
+		challenger, err := s.findChallenger(playerID, req.ChallengeID)
+		if err != nil {
+			return reply{}, err
+		}
+		battle, err := s.manager.AcceptChallenge(req.ChallengeID, challenger.player, session.player)
+		if err != nil {
+			return reply{}, err
+		}
+		return ok(BattleInfo{BattleID: battle.ID, OpponentID: challenger.player.GetID()}), nil
+
+	case MsgDeclineChallenge:
+		var req ChallengeReply
+		if err := msg.DecodePayload(&req); err != nil {
+			return reply{}, err
+		}
+		return ok(nil), s.manager.DeclineChallenge(req.ChallengeID, playerID)
+
+	case MsgJoinQueue:
+		var req QueueRequest
+		if len(msg.Payload) > 0 {
+			if err := msg.DecodePayload(&req); err != nil {
+				return reply{}, err
+			}
+		}
+		format, err := pokebat.GetFormat(req.Format)
+		if err != nil {
+			return reply{}, err
+		}
+		return ok(nil), s.manager.JoinQueue(session.player, format)
+
+	case MsgLeaveQueue:
+		return ok(nil), s.manager.LeaveQueue(playerID)
+
+	case MsgReady:
+		battle, err := s.manager.GetPlayerBattle(playerID)
+		if err != nil {
+			return reply{}, err
+		}
+		return ok(nil), battle.SetPlayerReady(playerID)
+
+	case MsgBattleMove:
+		var req BattleMoveRequest
+		if err := msg.DecodePayload(&req); err != nil {
+			return reply{}, err
+		}
+		battle, err := s.manager.GetPlayerBattle(playerID)
+		if err != nil {
+			return reply{}, err
+		}
+		return ok(nil), battle.ExecuteMoveAt(playerID, req.Move, req.Target)
+
+	case MsgBattleSwitch:
+		var req BattleSwitchRequest
+		if err := msg.DecodePayload(&req); err != nil {
+			return reply{}, err
+		}
+		battle, err := s.manager.GetPlayerBattle(playerID)
+		if err != nil {
+			return reply{}, err
+		}
+		return ok(nil), battle.SwitchPokemon(playerID, req.Index)
+
+	case MsgChallengeNPC:
+		var req ChallengeNPCRequest
+		if err := msg.DecodePayload(&req); err != nil {
+			return reply{}, err
+		}
+		format, err := pokebat.GetFormat(req.Format)
+		if err != nil {
+			return reply{}, err
+		}
+		battle, err := s.manager.StartNPCBattle(session.player, req.NPCID, format)
+		if err != nil {
+			return reply{}, err
+		}
+		return ok(BattleInfo{BattleID: battle.ID, OpponentID: req.NPCID}), nil
+
+	case MsgSurrender:
+		battle, err := s.manager.GetPlayerBattle(playerID)
+		if err != nil {
+			return reply{}, err
+		}
+		return ok(nil), battle.Surrender(playerID)
+
+	case MsgBattleCapture:
+		battle, err := s.manager.GetPlayerBattle(playerID)
+		if err != nil {
+			return reply{}, err
+		}
+		captured, err := battle.TryCapture(playerID)
+		if err != nil {
+			return reply{}, err
+		}
+		return ok(CaptureResult{Captured: captured}), nil
+
+	case MsgSpectate:
+		var req SpectateRequest
+		if err := msg.DecodePayload(&req); err != nil {
+			return reply{}, err
+		}
+		return s.handleSpectate(session, req.BattleID)
+
+	case MsgStopSpectating:
+		session.setSpectating(nil)
+		return ok(nil), nil
+
+	case MsgListBattles:
+		return ok(BattleList{Battles: s.manager.ListLiveBattles()}), nil
+
+	case MsgListFormats:
+		return ok(FormatList{Formats: pokebat.ListFormats()}), nil
+
+	case MsgLeaderboard:
+		var req LeaderboardRequest
+		if len(msg.Payload) > 0 {
+			if err := msg.DecodePayload(&req); err != nil {
+				return reply{}, err
+			}
+		}
+		if req.Limit <= 0 {
+			req.Limit = constants.DefaultLeaderboard
+		}
+		if req.Limit > constants.MaxLeaderboard {
+			req.Limit = constants.MaxLeaderboard
+		}
+		entries, err := models.Leaderboard(req.Limit)
+		if err != nil {
+			return reply{}, err
+		}
+		return ok(LeaderboardReply{Entries: entries}), nil
+
+	case MsgPlayerRecord:
+		var req PlayerRecordRequest
+		if len(msg.Payload) > 0 {
+			if err := msg.DecodePayload(&req); err != nil {
+				return reply{}, err
+			}
+		}
+		if req.PlayerID == "" {
+			req.PlayerID = playerID
+		}
+		return s.handlePlayerRecord(req.PlayerID)
+
+	case MsgTournamentCreate, MsgTournamentRegister, MsgTournamentUnregister,
+		MsgTournamentStart, MsgTournamentList, MsgTournamentInfo:
+		return s.handleTournament(session, msg)
+
+	case MsgMove, MsgView, MsgStartAuto, MsgStopAuto:
+		return s.handleWorld(session, msg)
+	}
+
+	return reply{}, fmt.Errorf("unknown message type: %s", msg.Type)
+}
+
+func (s *Server) handleLogin(session *Session, msg Message) (reply, error) {
+	if session.player != nil {
+		return reply{}, fmt.Errorf("already logged in")
+	}
+
+	var req LoginRequest
+	if err := msg.DecodePayload(&req); err != nil {
+		return reply{}, err
+	}
+	if err := models.ValidatePlayerID(req.PlayerID); err != nil {
+		return reply{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, online := s.sessions[req.PlayerID]; online {
+		return reply{}, fmt.Errorf("player %s is already online", req.PlayerID)
+	}
+
+	player, err := models.LoadPlayer(req.PlayerID, s.config)
+	if err != nil {
+		return reply{}, err
+	}
+	if s.world != nil {
+		if err := s.world.AddPlayer(player); err != nil {
+			player.Cleanup()
+			return reply{}, err
+		}
+	}
+	session.player = player
+	s.sessions[req.PlayerID] = session
+	if s.world != nil {
+		s.forwardWorldEvents(session)
+	}
+	return ok(nil), nil
+}
+
+// logout - Gỡ session khỏi server và lưu player
+func (s *Server) logout(session *Session) {
+	if session.player == nil {
+		return
+	}
+	playerID := session.player.GetID()
+
+	s.mu.Lock()
+	if s.sessions[playerID] == session {
+		delete(s.sessions, playerID)
+	}
+	s.mu.Unlock()
+
+	s.manager.LeaveQueue(playerID)
+	s.manager.Forfeit(playerID)
+	session.setSpectating(nil)
+	if s.world != nil {
+		session.stopWorldEvents()
+		s.world.RemovePlayer(session.player)
+	}
+	if err := session.player.Cleanup(); err != nil {
+		log.Printf("Failed to save player %s: %v", playerID, err)
+	}
+}
+
+// handleWorld - Xử lý lệnh di chuyển và xem vùng xung quanh trong world
+func (s *Server) handleWorld(session *Session, msg Message) (reply, error) {
+	if s.world == nil {
+		return reply{}, fmt.Errorf("world is not enabled")
+	}
+
+	switch msg.Type {
+	case MsgStartAuto:
+		req := StartAutoRequest{Strategy: constants.AutoStrategyRandom, Duration: constants.DefaultAutoModeDuration}
+		if len(msg.Payload) > 0 {
+			if err := msg.DecodePayload(&req); err != nil {
+				return reply{}, err
+			}
+		}
+		if req.Strategy == "" {
+			req.Strategy = constants.AutoStrategyRandom
+		}
+		if req.Duration == 0 {
+			req.Duration = constants.DefaultAutoModeDuration
+		}
+		if err := s.world.StartAutoMode(session.player, time.Duration(req.Duration)*time.Second, req.Strategy); err != nil {
+			return reply{}, err
+		}
+		auto := session.player.GetAutoMode()
+		return ok(AutoModeInfo{Strategy: auto.Strategy, EndTime: auto.EndTime}), nil
+
+	case MsgStopAuto:
+		s.world.StopAutoMode(session.player.GetID())
+		return ok(nil), nil
+	}
+
+	if msg.Type == MsgMove {
+		var req MoveRequest
+		if err := msg.DecodePayload(&req); err != nil {
+			return reply{}, err
+		}
+		direction, err := parseDirection(req.Direction)
+		if err != nil {
+			return reply{}, err
+		}
+		result, err := s.world.Move(session.player, direction)
+		if err != nil {
+			return reply{}, err
+		}
+		captured := make([]string, 0, len(result.Captures))
+		for _, capture := range result.Captures {
+			captured = append(captured, capture.Pokemon.Number)
+		}
+		return ok(MoveReply{Captured: captured, View: s.viewWindow(session.player, constants.DefaultViewRadius)}), nil
+	}
+
+	var req ViewRequest
+	if len(msg.Payload) > 0 {
+		if err := msg.DecodePayload(&req); err != nil {
+			return reply{}, err
+		}
+	}
+	if req.Radius <= 0 {
+		req.Radius = constants.DefaultViewRadius
+	}
+	if req.Radius > constants.MaxViewRadius {
+		req.Radius = constants.MaxViewRadius
+	}
+	return ok(s.viewWindow(session.player, req.Radius)), nil
+}
+
+// viewWindow - Vùng vuông cạnh 2*radius+1 ô quanh player, không gồm chính player
+func (s *Server) viewWindow(player *models.Player, radius int) ViewWindow {
+	pos := player.GetPosition()
+	view := s.world.QueryRect(pokecat.Rect{X: pos.X - radius, Y: pos.Y - radius, Width: 2*radius + 1, Height: 2*radius + 1})
+
+	window := ViewWindow{
+		Position: pos,
+		Area:     view.Area,
+		Terrain:  s.world.TerrainRows(view.Area),
+		Pokemon:  make([]ViewPokemon, 0, len(view.Pokemon)),
+		Players:  make([]ViewPlayer, 0, len(view.Players)),
+	}
+	for _, visible := range view.Pokemon {
+		window.Pokemon = append(window.Pokemon, ViewPokemon{
+			X:      visible.X,
+			Y:      visible.Y,
+			Number: visible.Pokemon.Number,
+			Name:   visible.Pokemon.Name,
+			Level:  visible.Pokemon.Level,
+		})
+	}
+	for _, visible := range view.Players {
+		if visible.PlayerID == player.GetID() {
+			continue
+		}
+		window.Players = append(window.Players, ViewPlayer{X: visible.X, Y: visible.Y, PlayerID: visible.PlayerID})
+	}
+	return window
+}
+
+// parseDirection - Đọc hướng đi up, down, left, right
+func parseDirection(direction string) (constants.Direction, error) {
+	switch direction {
+	case "up":
+		return constants.DirectionUp, nil
+	case "down":
+		return constants.DirectionDown, nil
+	case "left":
+		return constants.DirectionLeft, nil
+	case "right":
+		return constants.DirectionRight, nil
+	}
+	return 0, fmt.Errorf("invalid direction: %s", direction)
+}
+
+// handleTournament - Xử lý các lệnh giải đấu
+func (s *Server) handleTournament(session *Session, msg Message) (reply, error) {
+	if s.tournaments == nil {
+		return reply{}, fmt.Errorf("tournaments are not enabled")
+	}
+	playerID := session.player.GetID()
+
+	if msg.Type == MsgTournamentList {
+		return ok(TournamentList{Tournaments: s.tournaments.List()}), nil
+	}
+	if msg.Type == MsgTournamentCreate {
+		var req TournamentCreateRequest
+		if err := msg.DecodePayload(&req); err != nil {
+			return reply{}, err
+		}
+		format, err := pokebat.GetFormat(req.BattleFormat)
+		if err != nil {
+			return reply{}, err
+		}
+		summary, err := s.tournaments.Create(playerID, req.Name, req.Format, format, req.MaxPlayers, req.SwissRounds)
+		if err != nil {
+			return reply{}, err
+		}
+		return ok(summary), nil
+	}
+
+	var req TournamentRequest
+	if err := msg.DecodePayload(&req); err != nil {
+		return reply{}, err
+	}
+	switch msg.Type {
+	case MsgTournamentRegister:
+		return ok(nil), s.tournaments.Register(req.TournamentID, session.player)
+	case MsgTournamentUnregister:
+		return ok(nil), s.tournaments.Unregister(req.TournamentID, playerID)
+	case MsgTournamentStart:
+		return ok(nil), s.tournaments.Start(req.TournamentID, playerID)
+	}
+
+	t, err := s.tournaments.Get(req.TournamentID)
+	if err != nil {
+		return reply{}, err
+	}
+	standings, err := s.tournaments.Standings(req.TournamentID)
+	if err != nil {
+		return reply{}, err
+	}
+	return ok(TournamentInfo{Tournament: t, Standings: standings}), nil
+}
+
+// handlePlayerRecord - Lấy thành tích từ player đang online, hoặc từ file nếu offline
+func (s *Server) handlePlayerRecord(playerID string) (reply, error) {
+	if err := models.ValidatePlayerID(playerID); err != nil {
+		return reply{}, err
+	}
+	if session, err := s.getSession(playerID); err == nil {
+		return ok(PlayerRecordReply{PlayerID: playerID, Record: session.player.GetBattleRecord()}), nil
+	}
+	record, err := models.LoadBattleRecord(playerID)
+	if err != nil {
+		return reply{}, err
+	}
+	return ok(PlayerRecordReply{PlayerID: playerID, Record: record}), nil
+}
+
+func (s *Server) getSession(playerID string) (*Session, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, exists := s.sessions[playerID]
+	if !exists {
+		return nil, fmt.Errorf("player %s is not online", playerID)
+	}
+	return session, nil
+}
+
+func (s *Server) findChallenger(playerID, challengeID string) (*Session, error) {
+	for _, challenge := range s.manager.GetChallengesFor(playerID) {
+		if challenge.ID == challengeID {
+			return s.getSession(challenge.ChallengerID)
+		}
+	}
+	return nil, fmt.Errorf(constants.ErrChallengeNotFound)
+}
+
+// handleBattleCreated - Báo battle mới cho hai player và chuyển tiếp event battle
+func (s *Server) handleBattleCreated(battle *pokebat.Battle) {
+	participants := [][2]string{
+		{battle.Player1.ID, battle.Player2.ID},
+		{battle.Player2.ID, battle.Player1.ID},
+	}
+
+	for _, ids := range participants {
+		session, err := s.getSession(ids[0])
+		if err != nil {
+			continue
+		}
+		session.send(MsgBattleCreated, BattleInfo{BattleID: battle.ID, OpponentID: ids[1]})
+		events, unsubscribe := battle.Subscribe(constants.EventBufferSize)
+		s.forwardEvents(session, events, unsubscribe)
+	}
+}
+
+// handleSpectate - Cho session xem battle, thay thế battle đang xem trước đó
+func (s *Server) handleSpectate(session *Session, battleID string) (reply, error) {
+	battle, err := s.manager.GetBattle(battleID)
+	if err != nil {
+		return reply{}, err
+	}
+	if battle.HasPlayer(session.player.GetID()) {
+		return reply{}, fmt.Errorf("cannot spectate your own battle")
+	}
+
+	history, events, unsubscribe, err := battle.Spectate(constants.EventBufferSize)
+	if err != nil {
+		return reply{}, err
+	}
+	session.setSpectating(unsubscribe)
+	s.forwardEvents(session, events, unsubscribe)
+	return ok(SpectateInfo{Battle: battle.Summary(), History: history}), nil
+}
+
+// forwardEvents - Đẩy event battle xuống session cho tới khi battle, đăng ký hoặc session kết thúc
+func (s *Server) forwardEvents(session *Session, events <-chan pokebat.Event, unsubscribe func()) {
+	go func() {
+		defer unsubscribe()
+		for {
+			select {
+			case event, open := <-events:
+				if !open {
+					return
+				}
+				session.send(MsgBattleEvent, event)
+			case <-session.done:
+				return
+			}
+		}
+	}()
+}
+
+// forwardWorldEvents - Đăng ký event world trong vùng nhìn thấy của player và đẩy xuống session.
+// Client quá chậm bị đóng đăng ký; khi đó server gửi lại toàn bộ vùng nhìn thấy rồi đăng ký lại.
+func (s *Server) forwardWorldEvents(session *Session) {
+	sub := s.world.Subscribe(pokecat.SubscribeOptions{
+		Follow: session.player,
+		Radius: constants.DefaultViewRadius,
+		Policy: pokecat.CloseOnLag,
+	})
+	session.worldMu.Lock()
+	session.worldEvents = sub
+	session.worldMu.Unlock()
+
+	go func() {
+		defer sub.Close()
+		for {
+			select {
+			case event, open := <-sub.Events():
+				if !open {
+					if sub.Lagged() && session.isWorldSubscription(sub) {
+						session.send(MsgWorldResync, s.viewWindow(session.player, constants.DefaultViewRadius))
+						s.forwardWorldEvents(session)
+					}
+					return
+				}
+				session.send(MsgWorldEvent, event)
+			case <-session.done:
+				return
+			}
+		}
+	}()
+}
+
+// isWorldSubscription - sub vẫn là đăng ký event world hiện tại của session
+func (session *Session) isWorldSubscription(sub *pokecat.Subscription) bool {
+	session.worldMu.Lock()
+	defer session.worldMu.Unlock()
+	return session.worldEvents == sub
+}
+
+// stopWorldEvents - Hủy đăng ký event world khi player rời world
+func (session *Session) stopWorldEvents() {
+	session.worldMu.Lock()
+	defer session.worldMu.Unlock()
+
+	if session.worldEvents != nil {
+		session.worldEvents.Close()
+		session.worldEvents = nil
+	}
+}
+
+// setSpectating - Đổi battle đang xem, hủy đăng ký battle cũ
+func (session *Session) setSpectating(unsubscribe func()) {
+	session.spectateMu.Lock()
+	defer session.spectateMu.Unlock()
+
+	if session.stopSpectating != nil {
+		session.stopSpectating()
+	}
+	session.stopSpectating = unsubscribe
+}
+
+// send - Gửi message tới client, bỏ qua nếu client quá chậm
+func (session *Session) send(msgType MessageType, payload interface{}) {
+	session.sendReply("", msgType, payload)
+}
+
+func (session *Session) sendReply(requestID string, msgType MessageType, payload interface{}) {
+	msg, err := NewMessage(msgType, payload)
+	if err != nil {
+		log.Printf("Failed to build %s message: %v", msgType, err)
+		return
+	}
+	msg.RequestID = requestID
+
+	select {
+	case session.out <- msg:
+	case <-session.done:
+	default:
+		log.Printf("Dropping %s message for slow client %s", msgType, session.conn.RemoteAddr())
+	}
+}
+
+func (session *Session) writeLoop(writeTimeout time.Duration) {
+	for {
+		select {
+		case msg := <-session.out:
+			session.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := WriteMessage(session.conn, msg); err != nil {
+				session.close()
+				return
+			}
+		case <-session.done:
+			return
+		}
+	}
+}
+
+func (session *Session) close() {
+	session.closeOnce.Do(func() {
+		close(session.done)
+		session.conn.Close()
+	})
+}